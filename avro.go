@@ -0,0 +1,156 @@
+package bodkin
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+var avroNameMatcher = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+type avroRecord struct {
+	Type      string      `json:"type"`
+	Name      string      `json:"name"`
+	Namespace string      `json:"namespace,omitempty"`
+	Fields    []avroField `json:"fields"`
+}
+
+type avroField struct {
+	Name    string          `json:"name"`
+	Type    any             `json:"type"`
+	Default json.RawMessage `json:"default,omitempty"`
+}
+
+// ExportAvroSchema returns the unified schema as an Avro record schema (.avsc) named
+// recordName in namespace, which can be empty.
+//
+// Nullable fields are unions of null and their type, with a null default. Nested
+// structs are records named after recordName and their path, lists are arrays and
+// maps are Avro maps. Timestamps, dates and times use the matching Avro logical
+// types, nanosecond times without one fall back to long.
+// Field names must be valid Avro names, see WithNameSanitizer.
+func (u *Bodkin) ExportAvroSchema(recordName, namespace string) ([]byte, error) {
+	sc, err := u.Schema()
+	if err != nil {
+		return nil, err
+	}
+	if !avroNameMatcher.MatchString(recordName) {
+		return nil, fmt.Errorf("invalid avro record name %q", recordName)
+	}
+	rec, err := avroRecordOf(recordName, sc.Fields())
+	if err != nil {
+		return nil, err
+	}
+	rec.Namespace = namespace
+	return json.MarshalIndent(rec, "", "  ")
+}
+
+func avroRecordOf(name string, fields []arrow.Field) (*avroRecord, error) {
+	rec := &avroRecord{Type: "record", Name: name, Fields: make([]avroField, 0, len(fields))}
+	for _, f := range fields {
+		if !avroNameMatcher.MatchString(f.Name) {
+			return nil, fmt.Errorf("invalid avro field name %q in %s", f.Name, name)
+		}
+		t, err := avroType(name+"_"+f.Name, f.Type)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s : %w", name, f.Name, err)
+		}
+		af := avroField{Name: f.Name, Type: t}
+		if f.Nullable && f.Type.ID() != arrow.NULL {
+			af.Type = []any{"null", t}
+			af.Default = json.RawMessage("null")
+		}
+		rec.Fields = append(rec.Fields, af)
+	}
+	return rec, nil
+}
+
+// avroType returns the Avro type of dt, name is used to name nested records.
+func avroType(name string, dt arrow.DataType) (any, error) {
+	switch t := dt.(type) {
+	case *arrow.StructType:
+		return avroRecordOf(name, t.Fields())
+	case *arrow.MapType:
+		if !isStringType(t.KeyType().ID()) {
+			return nil, fmt.Errorf("avro map keys must be strings, not %v", t.KeyType())
+		}
+		values, err := avroType(name+"_value", t.ItemType())
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"type": "map", "values": values}, nil
+	case arrow.ListLikeType:
+		return avroArray(name, t.ElemField())
+	case *arrow.DictionaryType:
+		return avroType(name, t.ValueType)
+	case *arrow.RunEndEncodedType:
+		return avroType(name, t.Encoded())
+	case *arrow.FixedSizeBinaryType:
+		return map[string]any{"type": "fixed", "name": name, "size": t.ByteWidth}, nil
+	case *arrow.Decimal128Type:
+		return map[string]any{"type": "bytes", "logicalType": "decimal", "precision": t.Precision, "scale": t.Scale}, nil
+	case *arrow.Decimal256Type:
+		return map[string]any{"type": "bytes", "logicalType": "decimal", "precision": t.Precision, "scale": t.Scale}, nil
+	case *arrow.TimestampType:
+		switch t.Unit {
+		case arrow.Millisecond:
+			return map[string]any{"type": "long", "logicalType": "timestamp-millis"}, nil
+		case arrow.Microsecond:
+			return map[string]any{"type": "long", "logicalType": "timestamp-micros"}, nil
+		case arrow.Nanosecond:
+			return map[string]any{"type": "long", "logicalType": "timestamp-nanos"}, nil
+		}
+		return "long", nil
+	case *arrow.Time32Type:
+		if t.Unit == arrow.Millisecond {
+			return map[string]any{"type": "int", "logicalType": "time-millis"}, nil
+		}
+		return "int", nil
+	case *arrow.Time64Type:
+		if t.Unit == arrow.Microsecond {
+			return map[string]any{"type": "long", "logicalType": "time-micros"}, nil
+		}
+		return "long", nil
+	}
+	switch dt.ID() {
+	case arrow.NULL:
+		return "null", nil
+	case arrow.BOOL:
+		return "boolean", nil
+	case arrow.INT8, arrow.INT16, arrow.INT32, arrow.UINT8, arrow.UINT16:
+		return "int", nil
+	case arrow.INT64, arrow.UINT32, arrow.UINT64:
+		return "long", nil
+	case arrow.FLOAT16, arrow.FLOAT32:
+		return "float", nil
+	case arrow.FLOAT64:
+		return "double", nil
+	case arrow.STRING, arrow.LARGE_STRING:
+		return "string", nil
+	case arrow.BINARY, arrow.LARGE_BINARY:
+		return "bytes", nil
+	case arrow.DATE32:
+		return map[string]any{"type": "int", "logicalType": "date"}, nil
+	case arrow.DATE64:
+		return map[string]any{"type": "long", "logicalType": "timestamp-millis"}, nil
+	}
+	return nil, fmt.Errorf("no avro type for %v", dt)
+}
+
+// avroArray returns the Avro array type of list elements elem.
+func avroArray(name string, elem arrow.Field) (any, error) {
+	items, err := avroType(name+"_elem", elem.Type)
+	if err != nil {
+		return nil, err
+	}
+	if elem.Nullable && elem.Type.ID() != arrow.NULL {
+		items = []any{"null", items}
+	}
+	return map[string]any{"type": "array", "items": items}, nil
+}
+
+func isStringType(t arrow.Type) bool {
+	return t == arrow.STRING || t == arrow.LARGE_STRING
+}
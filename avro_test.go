@@ -0,0 +1,70 @@
+package bodkin
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestExportAvroSchema(t *testing.T) {
+	u := NewBodkin(WithInferTimeUnits())
+	if err := u.Unify(`{"id":1,"at":"2024-01-02T03:04:05Z","tags":["x"],"user":{"age":2}}`); err != nil {
+		t.Fatal(err)
+	}
+	avsc, err := u.ExportAvroSchema("Event", "com.example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rec struct {
+		Type, Name, Namespace string
+		Fields                []struct {
+			Name    string
+			Type    json.RawMessage
+			Default json.RawMessage
+		}
+	}
+	if err := json.Unmarshal(avsc, &rec); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Type != "record" || rec.Name != "Event" || rec.Namespace != "com.example" {
+		t.Errorf("record = %s %s %s, want record Event com.example", rec.Type, rec.Name, rec.Namespace)
+	}
+	want := map[string]string{
+		"at":   `["null",{"logicalType":"timestamp-micros","type":"long"}]`,
+		"id":   `["null","long"]`,
+		"tags": `["null",{"items":["null","string"],"type":"array"}]`,
+		"user": `["null",{"type":"record","name":"Event_user","fields":[{"name":"age","type":["null","long"],"default":null}]}]`,
+	}
+	if len(rec.Fields) != len(want) {
+		t.Fatalf("fields = %s, want %d", avsc, len(want))
+	}
+	for _, f := range rec.Fields {
+		var got, w any
+		if err := json.Unmarshal(f.Type, &got); err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal([]byte(want[f.Name]), &w); err != nil {
+			t.Fatalf("field %s: %v", f.Name, err)
+		}
+		gb, _ := json.Marshal(got)
+		wb, _ := json.Marshal(w)
+		if string(gb) != string(wb) {
+			t.Errorf("%s type = %s, want %s", f.Name, gb, wb)
+		}
+		if string(f.Default) != "null" {
+			t.Errorf("%s default = %s, want null", f.Name, f.Default)
+		}
+	}
+}
+
+func TestExportAvroSchemaInvalidNames(t *testing.T) {
+	u := NewBodkin()
+	if err := u.Unify(`{"a-b":1}`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := u.ExportAvroSchema("Event", ""); err == nil {
+		t.Error("ExportAvroSchema() exported field a-b, want an invalid name error")
+	}
+	if _, err := u.ExportAvroSchema("1Event", ""); err == nil {
+		t.Error("ExportAvroSchema(1Event) succeeded, want an invalid name error")
+	}
+}
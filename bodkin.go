@@ -5,8 +5,11 @@ package bodkin
 
 import (
 	"bufio"
+	"bytes"
+	stdjson "encoding/json"
 	"errors"
 	"fmt"
+	"hash/maphash"
 	"io"
 	"math"
 	"os"
@@ -34,6 +37,32 @@ type Field struct {
 	Childen int `json:"children,omitempty"`
 	// Evaluation failure reason
 	Issue error `json:"issue,omitempty"`
+	// Why the field is pending, set by PendingFields
+	Pending PendingReason `json:"pending,omitempty"`
+	// Arrow data type of an evaluated field
+	dataType arrow.DataType
+}
+
+// PendingReason is the kind of value a pending field's type could not be evaluated from.
+type PendingReason int
+
+const (
+	NotPending PendingReason = iota
+	NullField
+	EmptyArray
+	EmptyObject
+)
+
+func (p PendingReason) String() string {
+	switch p {
+	case NullField:
+		return "null value"
+	case EmptyArray:
+		return "empty array"
+	case EmptyObject:
+		return "empty object"
+	}
+	return "not pending"
 }
 
 const (
@@ -43,9 +72,12 @@ const (
 
 // Bodkin is a collection of field paths, describing the columns of a structured input(s).
 type Bodkin struct {
-	rr                     io.Reader
-	br                     *bufio.Reader
-	delim                  byte
+	rr             io.Reader
+	br             *bufio.Reader
+	delim          byte
+	scanBufferSize int
+	// field being built by Schema, for SchemaBuildError
+	building               *fieldPos
 	original               *fieldPos
 	old                    *fieldPos
 	new                    *fieldPos
@@ -55,11 +87,80 @@ type Bodkin struct {
 	untypedFields          *omap.OrderedMap[string, *fieldPos]
 	unificationCount       int
 	maxCount               int
+	sampleEvery            int
+	scanned                int
 	inferTimeUnits         bool
 	quotedValuesAreStrings bool
 	typeConversion         bool
+	nameSanitizer          func(string) string
+	resolveEmpty           bool
+	resolveEmptyAs         arrow.Type
+	firstRecordOrder       bool
+	sortedFields           bool
+	keyOrder               []string
+	conflictResolver       func(dotpath string, old, new arrow.DataType) (arrow.DataType, error)
+	conflicts              *omap.OrderedMap[string, Field]
+	exactIntegerWidth      bool
+	largeTypes             bool
+	nullOnlyAs             arrow.DataType
+	maxDepth               int
+	boolTrue, boolFalse    []string
+	numericAsFloat64       bool
+	allowNonFinite         bool
+	timeLayouts            []string
+	isoDurations           bool
+	durationUnit           arrow.TimeUnit
+	blankAsNull            bool
+	caseCollisionCheck     bool
+	caseInsensitiveMerge   bool
+	canonicalNames         map[string]string
+	renames                map[string]string
+	maxFieldCount          int
+	diverted               map[string]bool
+	rawNumbers             map[string]bool
+	date64                 map[string]bool
+	runEndEncoded          map[string]bool
+	geoJSON                bool
+	geoShapes              map[string]*geoShape
+	semanticFraction       float64
+	semantics              map[string]*semanticCounts
+	maxEnumValues          int
+	enums                  map[string]*enumSet
+	metaKey                string
+	fieldMeta              map[string]map[string]string
+	collectStats           bool
+	stats                  map[string]*fieldStats
+	statSeed               maphash.Seed
+	dupKeys                reader.DuplicateKeyPolicy
+	distinguishAbsent      bool
+	presence               map[string]*presence
+	presenceInputs         int
+	observingPresence      bool
+	inferFixedSizeList     bool
+	listLens               map[string]int
+	nullCounts             map[string]int
+	unified                int
+	copying                bool
 	err                    error
 	changes                error
+	changeLog              []Change
+	frozen                 bool
+	additiveOnly           bool
+	jsonBlobFallback       bool
+	boolIntAsInt64         bool
+	promoteSingletons      bool
+	lintRules              []LintRule
+	datumTransform         func([]byte) ([]byte, error)
+	valueField             string
+	bigIntegersAsString    bool
+	errorSink              io.Writer
+	errorCount             int
+	jsonStream             bool
+	stream                 *stdjson.Decoder
+	startOffset            int64
+	started                bool
+	scanOffset, scanStart  int64
+	batch                  reader.BatchDecoder
 }
 
 func (u *Bodkin) Opts() []Option { return u.opts }
@@ -72,13 +173,81 @@ func (u *Bodkin) NewReader(opts ...reader.Option) (*reader.DataReader, error) {
 	if schema == nil {
 		return nil, fmt.Errorf("nil schema")
 	}
-	u.Reader, err = reader.NewReader(schema, 0, opts...)
+	u.Reader, err = reader.NewReader(schema, 0, append(u.readerOpts(), opts...)...)
 	if err != nil {
 		return nil, err
 	}
 	return u.Reader, nil
 }
 
+// readerOpts returns the Reader options implied by the Bodkin's options.
+func (u *Bodkin) readerOpts() []reader.Option {
+	var opts []reader.Option
+	if u.boolTrue != nil || u.boolFalse != nil {
+		opts = append(opts, reader.WithBoolTokens(u.boolTrue, u.boolFalse))
+	}
+	if u.allowNonFinite {
+		opts = append(opts, reader.WithAllowNonFiniteFloats())
+	}
+	if len(u.timeLayouts) > 0 {
+		opts = append(opts, reader.WithTimeLayouts(u.timeLayouts...))
+	}
+	if u.jsonStream {
+		opts = append(opts, reader.WithJSONStream())
+	}
+	if u.blankAsNull {
+		opts = append(opts, reader.WithTreatBlankAsNull())
+	}
+	if u.dupKeys != reader.DuplicateKeyLastWins {
+		opts = append(opts, reader.WithDuplicateKeyPolicy(u.dupKeys))
+	}
+	if u.datumTransform != nil {
+		opts = append(opts, reader.WithDatumTransform(u.datumTransform))
+	}
+	if u.valueField != "" {
+		opts = append(opts, reader.WithWrapTopLevelValues(u.valueField))
+	}
+	if u.scanBufferSize > 0 {
+		opts = append(opts, reader.WithScanBufferSize(u.scanBufferSize))
+	}
+	if u.caseInsensitiveMerge {
+		opts = append(opts, reader.WithCaseInsensitiveKeys())
+	}
+	return opts
+}
+
+// prepareInput applies the input transformations set by options to raw JSON input.
+func (u *Bodkin) prepareInput(a any) (any, error) {
+	if u.allowNonFinite {
+		switch t := a.(type) {
+		case []byte:
+			a = reader.QuoteNonFiniteFloats(t)
+		case string:
+			a = string(reader.QuoteNonFiniteFloats([]byte(t)))
+		}
+	}
+	if u.datumTransform != nil {
+		var err error
+		if a, err = reader.TransformDatum(u.datumTransform, a); err != nil {
+			return nil, err
+		}
+	}
+	if u.valueField != "" {
+		a = reader.WrapTopLevelValue(a, u.valueField)
+	}
+	return a, nil
+}
+
+// inputMap decodes input a to a map after applying the input transformations set
+// by options.
+func (u *Bodkin) inputMap(a any) (map[string]any, error) {
+	a, err := u.prepareInput(a)
+	if err != nil {
+		return nil, err
+	}
+	return reader.InputMapWithPolicy(a, u.dupKeys)
+}
+
 // NewBodkin returns a new Bodkin value from a structured input.
 // Input must be a json byte slice or string, a Go struct with exported fields or map[string]any.
 // Any unpopulated fields, empty objects or empty slices in JSON or map[string]any inputs are skipped as their
@@ -90,6 +259,8 @@ func NewBodkin(opts ...Option) *Bodkin {
 func newBodkin(opts ...Option) *Bodkin {
 	b := &Bodkin{}
 	b.opts = opts
+	b.maxCount = math.MaxInt
+	b.delim = '\n'
 	for _, opt := range opts {
 		opt(b)
 	}
@@ -97,7 +268,18 @@ func newBodkin(opts ...Option) *Bodkin {
 	// Ordered map of known fields, keys are field dotpaths.
 	b.knownFields = omap.New[string, *fieldPos]()
 	b.untypedFields = omap.New[string, *fieldPos]()
-	b.maxCount = math.MaxInt
+	b.conflicts = omap.New[string, Field]()
+	b.nullCounts = make(map[string]int)
+	b.presence = make(map[string]*presence)
+	b.listLens = make(map[string]int)
+	b.geoShapes = make(map[string]*geoShape)
+	b.diverted = make(map[string]bool)
+	b.semantics = make(map[string]*semanticCounts)
+	b.enums = make(map[string]*enumSet)
+	b.fieldMeta = make(map[string]map[string]string)
+	b.canonicalNames = make(map[string]string)
+	b.stats = make(map[string]*fieldStats)
+	b.statSeed = maphash.MakeSeed()
 	return b
 }
 
@@ -111,10 +293,73 @@ func (u *Bodkin) CountPending() int {
 	return u.untypedFields.Len()
 }
 
-// Err returns a []Field that could not be evaluated to date.
+// Compact reconciles the known and untyped fields with the unified schema's tree,
+// removing stale entries left by type changes: known fields no longer in the tree,
+// untyped fields which were resolved since, and untyped fields whose parent is no
+// longer a struct, which can't be resolved. Known fields pointing at replaced nodes
+// are updated. It returns the number of known and untyped fields removed, after
+// which CountPending only counts fields which can still be evaluated.
+func (u *Bodkin) Compact() (known, untyped int) {
+	if u.old == nil {
+		return 0, 0
+	}
+	nodes := make(map[string]*fieldPos)
+	var walk func(f *fieldPos)
+	walk = func(f *fieldPos) {
+		for _, c := range f.children {
+			nodes[c.dotPath()] = c
+			walk(c)
+		}
+	}
+	walk(u.old)
+	var stale []string
+	moved := make(map[string]*fieldPos)
+	for pair := u.knownFields.Oldest(); pair != nil; pair = pair.Next() {
+		n, ok := nodes[pair.Key]
+		switch {
+		case !ok:
+			stale = append(stale, pair.Key)
+		case n != pair.Value:
+			moved[pair.Key] = n
+		}
+	}
+	for _, p := range stale {
+		u.knownFields.Delete(p)
+	}
+	for p, n := range moved {
+		u.knownFields.Set(p, n)
+	}
+	known = len(stale)
+	stale = stale[:0]
+	for pair := u.untypedFields.Oldest(); pair != nil; pair = pair.Next() {
+		if _, ok := nodes[pair.Key]; ok {
+			stale = append(stale, pair.Key)
+			continue
+		}
+		path := pair.Value.path
+		if len(path) <= 1 {
+			continue
+		}
+		parentPath := "$" + strings.Join(path[:len(path)-1], ".")
+		if _, ok := u.untypedFields.Get(parentPath); ok {
+			// resolved along with its parent
+			continue
+		}
+		if parent, ok := nodes[parentPath]; !ok || parent.field.Type.ID() != arrow.STRUCT {
+			stale = append(stale, pair.Key)
+		}
+	}
+	for _, p := range stale {
+		u.untypedFields.Delete(p)
+	}
+	return known, len(stale)
+}
+
+// Err returns a []Field that could not be evaluated to date, followed by
+// fields whose type conflicts could not be resolved.
 func (u *Bodkin) Err() []Field {
 	fp := u.sortMapKeysDesc(unknown)
-	var paths []Field = make([]Field, len(fp))
+	var paths []Field = make([]Field, len(fp), len(fp)+u.conflicts.Len())
 	for i, p := range fp {
 		f, _ := u.untypedFields.Get(p)
 		d := Field{Dotpath: f.dotPath(), Type: f.arrowType}
@@ -128,9 +373,57 @@ func (u *Bodkin) Err() []Field {
 		}
 		paths[i] = d
 	}
+	for pair := u.conflicts.Oldest(); pair != nil; pair = pair.Next() {
+		paths = append(paths, pair.Value)
+	}
 	return paths
 }
 
+// PendingFields returns the fields whose type could not be evaluated to date, in the
+// order they were first seen, with the reason they are pending.
+func (u *Bodkin) PendingFields() []Field {
+	fields := make([]Field, 0, u.untypedFields.Len())
+	for pair := u.untypedFields.Oldest(); pair != nil; pair = pair.Next() {
+		f := pair.Value
+		fields = append(fields, Field{Dotpath: f.dotPath(), Type: f.arrowType, Pending: pendingReason(f)})
+	}
+	return fields
+}
+
+// addConflict records a type conflict at field f that was not resolved,
+// only the latest conflict is kept for each field.
+func (u *Bodkin) addConflict(f *fieldPos, n *fieldPos, err error) {
+	u.conflicts.Set(f.dotPath(), Field{
+		Dotpath:  f.dotPath(),
+		Type:     f.field.Type.ID(),
+		dataType: f.field.Type,
+		Issue:    fmt.Errorf("%w %v : %v vs %v : %w", ErrFieldTypeConflict, f.dotPath(), f.field.Type, n.field.Type, err),
+	})
+}
+
+// FieldHistory returns the types held by the field at dotpath over its lifetime,
+// oldest first, ie. [INT64 FLOAT64 STRING] for an integer field upgraded twice.
+// A field whose type never changed has a single type, an unknown field none.
+func (u *Bodkin) FieldHistory(dotpath string) []arrow.Type {
+	f, ok := u.knownFields.Get(dotpath)
+	if !ok {
+		return nil
+	}
+	if len(f.history) == 0 {
+		return []arrow.Type{f.field.Type.ID()}
+	}
+	return slices.Clone(f.history)
+}
+
+// Freeze locks the unified schema: later inputs are still counted, but fields they add
+// or whose type differs from the frozen schema are recorded as conflicts in Err()
+// instead of changing it, and untyped fields are no longer resolved.
+// Freeze has no effect until the first input has been unified.
+func (u *Bodkin) Freeze() { u.frozen = u.old != nil }
+
+// Frozen reports whether the schema was frozen with Freeze.
+func (u *Bodkin) Frozen() bool { return u.frozen }
+
 // Changes returns a list of field additions and field type conversions done
 // in the lifetime of the Bodkin object.
 func (u *Bodkin) Changes() error { return u.changes }
@@ -139,7 +432,20 @@ func (u *Bodkin) Changes() error { return u.changes }
 func (u *Bodkin) Count() int { return u.unificationCount }
 
 // MaxCount returns the maximum number of datum to be evaluated for schema.
-func (u *Bodkin) MaxCount() int { return u.unificationCount }
+func (u *Bodkin) MaxCount() int { return u.maxCount }
+
+// ScanBufferSize returns the maximum size in bytes of a datum scanned from line-delimited
+// input, set with WithScanBufferSize, or bufio.MaxScanTokenSize by default.
+func (u *Bodkin) ScanBufferSize() int {
+	if u.scanBufferSize > 0 {
+		return u.scanBufferSize
+	}
+	return bufio.MaxScanTokenSize
+}
+
+// ScannedCount returns the number of datum read by UnifyScan to date, including
+// datum skipped by WithSampleEveryNth.
+func (u *Bodkin) ScannedCount() int { return u.scanned }
 
 // ResetCount resets the count of datum evaluated for schema to date.
 func (u *Bodkin) ResetCount() int {
@@ -164,7 +470,7 @@ func (u *Bodkin) Paths() []Field {
 		if !ok {
 			continue
 		}
-		d := Field{Dotpath: f.dotPath(), Type: f.arrowType}
+		d := Field{Dotpath: f.dotPath(), Type: f.arrowType, dataType: f.field.Type}
 		switch f.arrowType {
 		case arrow.STRUCT:
 			d.Childen = len(f.children)
@@ -217,30 +523,129 @@ func (u *Bodkin) Unify(a any) error {
 	if u.unificationCount > u.maxCount {
 		return fmt.Errorf("maxcount exceeded")
 	}
-	m, err := reader.InputMap(a)
+	a, err := u.prepareInput(a)
 	if err != nil {
 		u.err = fmt.Errorf("%v : %v", ErrInvalidInput, err)
 		return fmt.Errorf("%v : %v", ErrInvalidInput, err)
 	}
+	m, err := reader.InputMapWithPolicy(a, u.dupKeys)
+	if err != nil {
+		u.err = fmt.Errorf("%v : %v", ErrInvalidInput, err)
+		return fmt.Errorf("%v : %v", ErrInvalidInput, err)
+	}
+	u.unifyMap(a, m)
+	return nil
+}
+
+// UnifyChanged unifies a as Unify does and reports whether it changed the unified
+// schema: a was the first input, or a field was added or changed type, ie. to update
+// a schema registry only when the schema evolves.
+func (u *Bodkin) UnifyChanged(a any) (bool, error) {
+	first := u.old == nil
+	logged, diverted := len(u.changeLog), len(u.diverted)
+	if err := u.Unify(a); err != nil {
+		return false, err
+	}
+	if first || diverted == 0 && len(u.diverted) > 0 {
+		// the first diverted field adds the extra column
+		return u.old != nil, nil
+	}
+	for _, c := range u.changeLog[logged:] {
+		if c.Kind == ErrFieldAdded || c.Kind == ErrFieldTypeChanged {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// UnifyBatch unifies a batch of json datum, ie. a page of messages, in one call.
+// Datum which can't be decoded are skipped and their errors accumulated in Err(),
+// the error returned is the first one that stops the batch, ie. exceeding the
+// count set with WithMaxCount.
+//
+// The batch is decoded by a single stream decoder over a scratch buffer reused from
+// call to call, which is faster and allocates less than calling Unify for each datum.
+// Duplicate key policies other than the default decode each datum on their own.
+func (u *Bodkin) UnifyBatch(data [][]byte) error {
+	var errs error
+	defer func() {
+		if errs != nil {
+			u.err = errors.Join(u.err, errs)
+		}
+	}()
+	b := &u.batch
+	b.Reset()
+	idx := make([]int, len(data))
+	prepErrs := make([]error, len(data))
+	for i, datum := range data {
+		prepared, err := u.prepareInput(datum)
+		if err != nil {
+			prepErrs[i] = err
+			continue
+		}
+		idx[i] = b.Add(prepared.([]byte))
+	}
+	for i := range data {
+		if u.unificationCount > u.maxCount {
+			return fmt.Errorf("maxcount exceeded at batch index %d", i)
+		}
+		if prepErrs[i] != nil {
+			errs = errors.Join(errs, fmt.Errorf("%v : batch index %d : %v", ErrInvalidInput, i, prepErrs[i]))
+			continue
+		}
+		datum := b.Datum(idx[i])
+		var m map[string]any
+		var err error
+		if u.dupKeys == reader.DuplicateKeyLastWins {
+			m, err = b.Decode(idx[i])
+		} else {
+			m, err = reader.InputMapWithPolicy(datum, u.dupKeys)
+		}
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("%v : batch index %d : %v", ErrInvalidInput, i, err))
+			continue
+		}
+		u.unifyMap(datum, m)
+	}
+	return nil
+}
+
+// unifyMap merges the decoded input m, decoded from a, into the unified schema.
+func (u *Bodkin) unifyMap(a any, m map[string]any) {
+	if u.distinguishAbsent {
+		// Presence is only observed for whole inputs, whose paths are their dotpaths.
+		u.presenceInputs++
+		u.observingPresence = true
+		defer func() { u.observingPresence = false }()
+	}
 	if u.old == nil {
+		if u.firstRecordOrder {
+			u.keyOrder, _ = reader.InputKeys(a)
+			defer func() { u.keyOrder = nil }()
+		}
 		// Keep an immutable copy of the initial evaluation.
+		// Per-input statistics are only collected once, in the mutable copy.
+		u.copying = true
 		g := newFieldPos(u)
 		mapToArrow(g, m)
 		u.original = g
+		u.copying = false
 		// Identical to above except this one can be mutated with Unify.
 		f := newFieldPos(u)
 		mapToArrow(f, m)
 		u.old = f
-		return nil
+		u.unified++
+		return
 	}
-	f := newFieldPos(u)
+	f := newPooledFieldPos(u)
 	mapToArrow(f, m)
+	u.new.release()
 	u.new = f
 	for _, field := range u.new.children {
 		u.merge(field, nil)
 	}
 	u.unificationCount++
-	return nil
+	u.unified++
 }
 
 // UnifyScan reads from a provided io.Reader and merges each datum's structured input's column definition
@@ -254,6 +659,9 @@ func (u *Bodkin) UnifyScan() error {
 	if u.unificationCount > u.maxCount {
 		return fmt.Errorf("maxcount exceeded")
 	}
+	if err := u.seekStart(); err != nil {
+		return err
+	}
 	defer func() error {
 		if rc := recover(); rc != nil {
 			u.err = errors.Join(u.err, err, fmt.Errorf("panic %v", rc))
@@ -261,7 +669,7 @@ func (u *Bodkin) UnifyScan() error {
 		return u.err
 	}()
 	for {
-		datumBytes, err := u.br.ReadBytes(u.delim)
+		datumBytes, err := u.readDatum()
 		if err != nil {
 			if errors.Is(err, io.EOF) {
 				u.err = nil
@@ -270,9 +678,25 @@ func (u *Bodkin) UnifyScan() error {
 			u.err = err
 			break
 		}
-		m, err := reader.InputMap(datumBytes)
+		u.scanned++
+		if u.sampleEvery > 1 && (u.scanned-1)%u.sampleEvery != 0 {
+			continue
+		}
+		if u.unificationCount > u.maxCount {
+			break
+		}
+		if u.old == nil && u.firstRecordOrder {
+			// first record is unified from its raw form to capture its key order
+			if err := u.Unify(datumBytes); err != nil {
+				u.err = errors.Join(u.err, err)
+				u.sinkError(datumBytes)
+			}
+			continue
+		}
+		m, err := u.inputMap(datumBytes)
 		if err != nil {
 			u.err = errors.Join(u.err, err)
+			u.sinkError(datumBytes)
 			continue
 		}
 		u.Unify(m)
@@ -280,6 +704,121 @@ func (u *Bodkin) UnifyScan() error {
 	return u.err
 }
 
+// readDatum returns the next datum from the io.Reader, split on the delimiter or, with
+// WithJSONStream, the next top-level JSON value, compacted to a single line.
+func (u *Bodkin) readDatum() ([]byte, error) {
+	if !u.jsonStream {
+		b, err := u.br.ReadBytes(u.delim)
+		u.scanOffset += int64(len(b))
+		return b, err
+	}
+	if u.stream == nil {
+		u.stream = stdjson.NewDecoder(u.br)
+	}
+	var raw stdjson.RawMessage
+	if err := u.stream.Decode(&raw); err != nil {
+		return nil, err
+	}
+	var b bytes.Buffer
+	if err := stdjson.Compact(&b, raw); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// sinkError counts an undecodable datum read by UnifyScan and writes it to the error
+// sink set with WithErrorSink, if any.
+func (u *Bodkin) sinkError(datum []byte) {
+	u.errorCount++
+	if u.errorSink == nil {
+		return
+	}
+	datum = bytes.TrimSuffix(datum, []byte{u.delim})
+	if _, err := u.errorSink.Write(append(datum, '\n')); err != nil {
+		u.err = errors.Join(u.err, fmt.Errorf("error sink : %w", err))
+	}
+}
+
+// ErrorCount returns the number of datum UnifyScan could not decode to date.
+func (u *Bodkin) ErrorCount() int { return u.errorCount }
+
+// UnifyAndRead reads from the provided io.Reader in a single pass, unifying each datum
+// and sending its data as Arrow records to out, in chunks of chunk rows as with
+// reader.WithChunk. The caller must release the records. out is not closed.
+//
+// Records are loaded with the schema frozen on the first datum: fields first seen in
+// later data are not loaded and data conflicting with the frozen types is reported as
+// an error. Later data is still unified, so that if the schema evolved during the scan,
+// an error wrapping ErrSchemaEvolved is returned, and the data must be read again
+// with the final Schema() to load the new fields.
+func (u *Bodkin) UnifyAndRead(out chan<- arrow.Record, chunk int) error {
+	if u.rr == nil {
+		return fmt.Errorf("no io.reader provided")
+	}
+	var (
+		frozen *arrow.Schema
+		pw     *io.PipeWriter
+		done   chan error
+		err    error
+	)
+	for {
+		datumBytes, rerr := u.readDatum()
+		if len(bytes.TrimSpace(datumBytes)) > 0 {
+			if uerr := u.Unify(datumBytes); uerr != nil {
+				err = errors.Join(err, uerr)
+			}
+			if pw == nil && u.old != nil {
+				var serr error
+				if frozen, serr = u.currentSchema(); serr != nil {
+					return errors.Join(err, serr)
+				}
+				var pr *io.PipeReader
+				pr, pw = io.Pipe()
+				opts := append(u.readerOpts(), reader.WithIOReader(pr, u.delim), reader.WithChunk(chunk))
+				rdr, nerr := reader.NewReader(frozen, 0, opts...)
+				if nerr != nil {
+					return errors.Join(err, nerr)
+				}
+				done = make(chan error, 1)
+				go func() {
+					for rdr.Next() {
+						rec := rdr.Record()
+						rec.Retain()
+						out <- rec
+					}
+					// drain the pipe so that writes don't block after a reader error
+					io.Copy(io.Discard, pr)
+					done <- rdr.Close()
+				}()
+			}
+			if pw != nil {
+				if datumBytes[len(datumBytes)-1] != u.delim {
+					datumBytes = append(datumBytes, u.delim)
+				}
+				if _, werr := pw.Write(datumBytes); werr != nil {
+					err = errors.Join(err, werr)
+					break
+				}
+			}
+		}
+		if rerr != nil {
+			if !errors.Is(rerr, io.EOF) {
+				err = errors.Join(err, rerr)
+			}
+			break
+		}
+	}
+	if pw == nil {
+		return err
+	}
+	pw.Close()
+	err = errors.Join(err, <-done)
+	if s, serr := u.currentSchema(); serr == nil && !s.Equal(frozen) {
+		err = errors.Join(err, ErrSchemaEvolved)
+	}
+	return err
+}
+
 // Unify merges structured input's column definition with the previously input's schema,
 // using a specified valid path as the root. An error is returned if the mergeAt path is
 // not found.
@@ -299,14 +838,15 @@ func (u *Bodkin) UnifyAtPath(a any, mergeAt string) error {
 		return fmt.Errorf("unitfyatpath %s : %v", mergeAt, ErrPathNotFound)
 	}
 
-	m, err := reader.InputMap(a)
+	m, err := u.inputMap(a)
 	if err != nil {
 		u.err = fmt.Errorf("%v : %v", ErrInvalidInput, err)
 		return fmt.Errorf("%v : %v", ErrInvalidInput, err)
 	}
 
-	f := newFieldPos(u)
+	f := newPooledFieldPos(u)
 	mapToArrow(f, m)
+	u.new.release()
 	u.new = f
 	for _, field := range u.new.children {
 		u.merge(field, mergePath)
@@ -315,47 +855,80 @@ func (u *Bodkin) UnifyAtPath(a any, mergeAt string) error {
 	return nil
 }
 
+// UnifyAtPathCreate is like UnifyAtPath, but if the mergeAt path does not exist it is
+// created as empty nested structs before merging. An error wrapping ErrPathConflict is
+// returned if an element of the path already exists and is not a struct.
+// Structs created for the path which remain empty can't be written to Parquet.
+func (u *Bodkin) UnifyAtPathCreate(a any, mergeAt string) error {
+	if u.old == nil {
+		return fmt.Errorf("bodkin not initialised")
+	}
+	if err := u.createPath(mergeAt); err != nil {
+		return err
+	}
+	return u.UnifyAtPath(a, mergeAt)
+}
+
+// createPath grafts empty structs for the missing elements of the mergeAt path.
+func (u *Bodkin) createPath(mergeAt string) error {
+	if len(mergeAt) == 0 || mergeAt == "$" {
+		return nil
+	}
+	f := u.old
+	for _, key := range strings.Split(strings.TrimPrefix(mergeAt, "$"), ".") {
+		c, err := f.getPath([]string{key})
+		if err != nil && u.frozen {
+			return fmt.Errorf("unifyatpath %s : %w", mergeAt, ErrSchemaFrozen)
+		}
+		if err != nil {
+			n := f.newChild(key)
+			name, meta := f.fieldName(key)
+			n.arrowType = arrow.STRUCT
+			n.isStruct = true
+			n.field = buildArrowField(name, arrow.StructOf(), meta, true)
+			f.graft(n)
+			c, _ = f.getPath([]string{key})
+		} else if c.field.Type == nil || c.field.Type.ID() != arrow.STRUCT {
+			return fmt.Errorf("unifyatpath %s : %v %w", mergeAt, c.dotPath(), ErrPathConflict)
+		}
+		f = c
+	}
+	return nil
+}
+
 // Schema returns the original Arrow schema generated from the structure/types of
-// the initial input, and a panic recovery error if the schema could not be created.
-func (u *Bodkin) OriginSchema() (*arrow.Schema, error) {
+// the initial input, and a *SchemaBuildError if the schema could not be created.
+func (u *Bodkin) OriginSchema() (s *arrow.Schema, err error) {
 	if u.old == nil {
 		return nil, fmt.Errorf("bodkin not initialised")
 	}
-	var s *arrow.Schema
-	defer func(s *arrow.Schema) (*arrow.Schema, error) {
-		if pErr := recover(); pErr != nil {
-			return nil, fmt.Errorf("schema problem: %v", pErr)
-		}
-		return s, nil
-	}(s)
+	defer u.recoverSchema(&s, &err)
 	var fields []arrow.Field
 	for _, c := range u.original.children {
+		u.building = c
 		fields = append(fields, c.field)
 	}
+	u.building = nil
 	s = arrow.NewSchema(fields, nil)
 	return s, nil
 }
 
 // Schema returns the current merged Arrow schema generated from the structure/types of
-// the input(s), and a panic recovery error if the schema could not be created.
+// the input(s), and a *SchemaBuildError if the schema could not be created.
 // If the Bodkin has a Reader and the schema has been updated since its creation, the Reader
 // will replaced with a new one matching the current schema. Any
-func (u *Bodkin) Schema() (*arrow.Schema, error) {
+func (u *Bodkin) Schema() (s *arrow.Schema, err error) {
 	if u.old == nil {
 		return nil, fmt.Errorf("bodkin not initialised")
 	}
-	var s *arrow.Schema
-	defer func(s *arrow.Schema) (*arrow.Schema, error) {
-		if pErr := recover(); pErr != nil {
-			return nil, fmt.Errorf("schema problem: %v", pErr)
-		}
-		return s, nil
-	}(s)
-	var fields []arrow.Field
-	for _, c := range u.old.children {
-		fields = append(fields, c.field)
+	defer u.recoverSchema(&s, &err)
+	if u.nullOnlyAs != nil {
+		u.resolveNullOnly()
 	}
-	s = arrow.NewSchema(fields, nil)
+	if u.resolveEmpty {
+		u.resolveUntyped()
+	}
+	s = u.buildSchema()
 	if u.Reader != nil {
 		if !u.Reader.Schema().Equal(s) {
 			u.Reader, _ = reader.NewReader(s, 0, u.Reader.Opts()...)
@@ -364,30 +937,214 @@ func (u *Bodkin) Schema() (*arrow.Schema, error) {
 	return s, nil
 }
 
+// currentSchema returns the current merged Arrow schema as Schema does, without
+// resolving untyped fields or replacing the Reader, so that the Bodkin is unchanged.
+func (u *Bodkin) currentSchema() (s *arrow.Schema, err error) {
+	defer u.recoverSchema(&s, &err)
+	return u.buildSchema(), nil
+}
+
+// buildSchema returns the Arrow schema of the unified tree.
+func (u *Bodkin) buildSchema() *arrow.Schema {
+	var fields []arrow.Field
+	for _, c := range u.old.children {
+		fields = append(fields, c.finalField(c.field))
+	}
+	u.building = nil
+	if len(u.diverted) > 0 {
+		fields = append(fields, u.extraField())
+	}
+	if u.sortedFields {
+		sortFields(fields)
+	}
+	return arrow.NewSchema(fields, nil)
+}
+
+// SchemaAtPath returns the schema of the struct field at dotpath in the current merged
+// schema, ie. "$results.dataobj", whose fields become the top-level fields. Both "$a.b"
+// and "$.a.b" forms are accepted. The elements of a list of structs are found at the
+// list's path followed by its name and ".elem", ie. "$results.results.elem".
+// An error is returned if the path is not found or is not a struct.
+func (u *Bodkin) SchemaAtPath(dotpath string) (*arrow.Schema, error) {
+	s, err := u.Schema()
+	if err != nil {
+		return nil, err
+	}
+	dotpath = cleanDotpath(dotpath)
+	if dotpath == "$" {
+		return s, nil
+	}
+	f, ok := u.knownFields.Get(dotpath)
+	if !ok {
+		return nil, fmt.Errorf("schemaatpath %s : %w", dotpath, ErrPathNotFound)
+	}
+	st, ok := f.finalField(f.field).Type.(*arrow.StructType)
+	if !ok {
+		return nil, fmt.Errorf("schemaatpath %s : %v is not a struct", dotpath, f.field.Type)
+	}
+	return arrow.NewSchema(st.Fields(), nil), nil
+}
+
+// cleanDotpath returns dotpath in the "$a.b" form used as key of the field maps,
+// accepting "$.a.b" and "a.b" forms.
+func cleanDotpath(dotpath string) string {
+	return "$" + strings.TrimPrefix(strings.TrimPrefix(dotpath, "$"), ".")
+}
+
+// resolveUntyped materializes fields that could not be evaluated to date using
+// the fallback type set with WithResolveEmptyAs. Empty arrays become lists of
+// the fallback type and null fields become the fallback type. Empty objects become
+// strings holding their JSON text, which the reader can load, unless the fallback
+// is arrow.NULL.
+func (u *Bodkin) resolveUntyped() {
+	var dt arrow.DataType
+	switch u.resolveEmptyAs {
+	case arrow.NULL:
+		dt = arrow.Null
+	case arrow.STRUCT, arrow.LIST:
+		dt = u.stringType()
+	case arrow.STRING:
+		dt = u.stringType()
+	default:
+		dt = arrowTypeID2Type(nil, u.resolveEmptyAs)
+		if dt == nil {
+			dt = u.stringType()
+		}
+	}
+	fp := u.sortMapKeysDesc(unknown)
+	// shallowest paths first, so that a resolved empty object's null children are skipped
+	slices.Reverse(fp)
+	for _, p := range fp {
+		f, ok := u.untypedFields.Get(p)
+		if !ok {
+			continue
+		}
+		if f.arrowType == arrow.STRUCT && dt.ID() != arrow.NULL {
+			u.materialize(f, u.stringType())
+			continue
+		}
+		u.materialize(f, dt)
+	}
+}
+
+// resolveNullOnly materializes fields that were present as null in every input
+// using the type set with WithNullOnlyAsType. With WithDistinguishAbsentFromNull,
+// inputs in which a field was absent are not taken into account.
+func (u *Bodkin) resolveNullOnly() {
+	for _, p := range u.sortMapKeysDesc(unknown) {
+		f, ok := u.untypedFields.Get(p)
+		if !ok || f.arrowType != arrow.NULL {
+			continue
+		}
+		nullOnly := u.nullCounts[p] >= u.unified
+		if u.distinguishAbsent {
+			nullOnly = u.nullOnly(p)
+		}
+		if !nullOnly {
+			continue
+		}
+		u.materialize(f, u.nullOnlyAs)
+	}
+}
+
+// materialize grafts the untyped field f into the unified schema as type dt, or
+// as a list of dt if f is an empty array. An error is returned if the field's parent
+// is not a struct in the unified schema.
+func (u *Bodkin) materialize(f *fieldPos, dt arrow.DataType) error {
+	if u.frozen {
+		return ErrSchemaFrozen
+	}
+	if len(f.path) == 0 {
+		return ErrPathNotFound
+	}
+	parent := u.old
+	if len(f.path) > 1 {
+		var err error
+		parent, err = u.old.getPath(f.path[:len(f.path)-1])
+		if err != nil {
+			return err
+		}
+		if parent.field.Type.ID() != arrow.STRUCT {
+			return ErrPathConflict
+		}
+	}
+	if _, err := parent.getPath(f.path[len(f.path)-1:]); err == nil {
+		return nil
+	}
+	n := parent.newChild(f.name)
+	name, meta := parent.fieldName(f.name)
+	reason := ErrUndefinedFieldType
+	switch f.arrowType {
+	case arrow.LIST:
+		reason = ErrUndefinedArrayElementType
+		n.arrowType = arrow.LIST
+		n.isList = true
+		n.field = buildArrowField(name, u.listOf(dt), meta, true)
+	default:
+		n.arrowType = dt.ID()
+		n.field = buildArrowField(name, dt, meta, true)
+	}
+	parent.graft(n)
+	// the untyped fields of an empty object are resolved along with it
+	prefix := f.dotPath() + "."
+	var under []string
+	for pair := u.untypedFields.Oldest(); pair != nil; pair = pair.Next() {
+		if strings.HasPrefix(pair.Key, prefix) {
+			under = append(under, pair.Key)
+		}
+	}
+	for _, p := range under {
+		u.untypedFields.Delete(p)
+	}
+	u.addChange(ErrFieldResolved, f.dotPath(), n.field.Type, fmt.Sprintf("%v, using %v", reason, n.field.Type.String()))
+	return nil
+}
+
+// HintListElem resolves the pending empty list field at dotpath as a list of elem,
+// for fields known to be lists whose elements have not been seen yet.
+// The field is added to the schema and removed from the untyped fields.
+func (u *Bodkin) HintListElem(dotpath string, elem arrow.DataType) error {
+	if u.old == nil {
+		return fmt.Errorf("bodkin not initialised")
+	}
+	if elem == nil {
+		return fmt.Errorf("hintlistelem %s : nil element type", dotpath)
+	}
+	f, ok := u.untypedFields.Get(dotpath)
+	if !ok {
+		return fmt.Errorf("hintlistelem %s : %w", dotpath, ErrPathNotFound)
+	}
+	if f.arrowType != arrow.LIST {
+		return fmt.Errorf("hintlistelem %s : not an empty list", dotpath)
+	}
+	if err := u.materialize(f, elem); err != nil {
+		return fmt.Errorf("hintlistelem %s : %w", dotpath, err)
+	}
+	return nil
+}
+
 // LastSchema returns the Arrow schema generated from the structure/types of
 // the most recent input. Any unpopulated fields, empty objects or empty slices are skipped.
-// ErrNoLatestSchema if Unify() has never been called. A panic recovery error is returned
+// ErrNoLatestSchema if Unify() has never been called. A *SchemaBuildError is returned
 // if the schema could not be created.
-func (u *Bodkin) LastSchema() (*arrow.Schema, error) {
+func (u *Bodkin) LastSchema() (s *arrow.Schema, err error) {
 	if u.new == nil {
 		return nil, ErrNoLatestSchema
 	}
-	var s *arrow.Schema
-	defer func(s *arrow.Schema) (*arrow.Schema, error) {
-		if pErr := recover(); pErr != nil {
-			return nil, fmt.Errorf("schema problem: %v", pErr)
-		}
-		return s, nil
-	}(s)
+	defer u.recoverSchema(&s, &err)
 	var fields []arrow.Field
 	for _, c := range u.new.children {
+		u.building = c
 		fields = append(fields, c.field)
 	}
+	u.building = nil
 	s = arrow.NewSchema(fields, nil)
 	return s, nil
 }
 
 // merge merges a new or changed field into the unified schema.
+// A conflict resolver set with WithConflictResolver is consulted first when
+// the field's type differs, the following rules apply if it returns a nil type.
 // Conflicting TIME, DATE, TIMESTAMP types are upgraded to STRING.
 // DATE can upgrade to TIMESTAMP.
 // INTEGER can upgrade to FLOAT.
@@ -400,9 +1157,13 @@ func (u *Bodkin) merge(n *fieldPos, mergeAt []string) {
 		nPath = n.path
 		nParentPath = n.parent.path
 	}
+	if u.frozen {
+		u.mergeFrozen(n, nPath, mergeAt)
+		return
+	}
 	if kin, err := u.old.getPath(nPath); err == ErrPathNotFound {
 		// root graft
-		if n.root == n.parent {
+		if n.root == n.parent && len(mergeAt) == 0 {
 			u.old.root.graft(n)
 		} else {
 			// branch graft
@@ -410,19 +1171,73 @@ func (u *Bodkin) merge(n *fieldPos, mergeAt []string) {
 			b.graft(n)
 		}
 	} else {
+		if kin.field.Type.ID() == arrow.NULL && n.field.Type.ID() != arrow.NULL {
+			// a column resolved as Null by Schema() while only null values were seen
+			kin.resolveNull(n)
+			return
+		}
+		if u.additiveOnly && (kin.field.Type.ID() != n.field.Type.ID() || len(n.children) == 0 && !arrow.TypeEqual(kin.field.Type, n.field.Type)) {
+			u.addConflict(kin, n, ErrFieldTypeKept)
+			return
+		}
+		if u.conflictResolver != nil && kin.field.Type.ID() != n.field.Type.ID() {
+			dt, err := u.conflictResolver(kin.dotPath(), kin.field.Type, n.field.Type)
+			switch {
+			case err != nil:
+				u.addConflict(kin, n, err)
+				return
+			case dt != nil:
+				if !arrow.TypeEqual(dt, kin.field.Type) {
+					kin.setType(dt)
+				}
+				return
+			}
+		}
+		if u.boolIntAsInt64 && isBoolIntPair(kin.field.Type.ID(), n.field.Type.ID()) {
+			if kin.field.Type.ID() == arrow.BOOL {
+				kin.setType(arrow.PrimitiveTypes.Int64)
+			}
+			kin.mergeIntRange(n)
+			return
+		}
+		if u.promoteSingletons && u.promoteSingleton(kin, n) {
+			return
+		}
+		if u.jsonBlobFallback {
+			if kin.jsonBlob {
+				// whatever is at the path is loaded as JSON text
+				return
+			}
+			if valueKind(kin.field.Type) != valueKind(n.field.Type) {
+				kin.setJSONBlob()
+				return
+			}
+		}
 		if u.typeConversion && (!kin.field.Equal(n.field) && kin.field.Type.ID() != n.field.Type.ID()) {
 			switch kin.field.Type.ID() {
 			case arrow.NULL:
 				break
-			case arrow.STRING:
+			case arrow.STRING, arrow.LARGE_STRING:
 				break
 			case arrow.INT8, arrow.INT16, arrow.INT32, arrow.INT64, arrow.UINT8, arrow.UINT16, arrow.UINT32, arrow.UINT64:
 				switch n.field.Type.ID() {
+				case arrow.DECIMAL128:
+					// an integer beyond the range of int64
+					kin.setType(n.field.Type)
 				case arrow.FLOAT16, arrow.FLOAT32, arrow.FLOAT64:
 					err := kin.upgradeType(n, arrow.FLOAT64)
 					if err != nil {
 						kin.err = errors.Join(kin.err, err)
 					}
+				case arrow.BOOL:
+					if u.boolTrue != nil || u.boolFalse != nil {
+						// a numeric bool token in an integer column
+						break
+					}
+					err := kin.upgradeType(n, arrow.STRING)
+					if err != nil {
+						kin.err = errors.Join(kin.err, err)
+					}
 				default:
 					err := kin.upgradeType(n, arrow.STRING)
 					if err != nil {
@@ -470,6 +1285,24 @@ func (u *Bodkin) merge(n *fieldPos, mergeAt []string) {
 						kin.err = errors.Join(kin.err, err)
 					}
 				}
+			case arrow.DECIMAL128:
+				switch n.field.Type.ID() {
+				case arrow.INT8, arrow.INT16, arrow.INT32, arrow.INT64, arrow.UINT8, arrow.UINT16, arrow.UINT32, arrow.UINT64:
+					break
+				default:
+					err := kin.upgradeType(n, arrow.STRING)
+					if err != nil {
+						kin.err = errors.Join(kin.err, err)
+					}
+				}
+			case arrow.BOOL:
+				switch n.field.Type.ID() {
+				case arrow.INT64:
+					if u.boolTrue != nil || u.boolFalse != nil {
+						// numeric bool tokens alongside other integers
+						kin.setType(arrow.PrimitiveTypes.Int64)
+					}
+				}
 			case arrow.TIMESTAMP:
 				switch n.field.Type.ID() {
 				case arrow.TIME64:
@@ -500,14 +1333,72 @@ func (u *Bodkin) merge(n *fieldPos, mergeAt []string) {
 						kin.err = errors.Join(kin.err, err)
 					}
 				}
+			case arrow.DURATION:
+				err := kin.upgradeType(n, arrow.STRING)
+				if err != nil {
+					kin.err = errors.Join(kin.err, err)
+				}
 			}
 		}
+		kin.mergeIntRange(n)
 		for _, v := range n.childmap {
 			u.merge(v, mergeAt)
 		}
 	}
 }
 
+// isBoolIntPair reports whether one of a and b is a boolean and the other an integer.
+func isBoolIntPair(a, b arrow.Type) bool {
+	return a == arrow.BOOL && arrow.IsInteger(b) || b == arrow.BOOL && arrow.IsInteger(a)
+}
+
+// promoteSingleton merges scalar field n into kin if it is a list of n's type, or list
+// field n into kin if kin is a scalar of its element type, making kin a list. It
+// reports whether they were merged.
+func (u *Bodkin) promoteSingleton(kin, n *fieldPos) bool {
+	if len(kin.children) > 0 || len(n.children) > 0 {
+		return false
+	}
+	switch {
+	case isListOf(kin.field.Type, n.field.Type):
+		return true
+	case isListOf(n.field.Type, kin.field.Type):
+		kin.isList = true
+		kin.setType(n.field.Type)
+		return true
+	}
+	return false
+}
+
+// isListOf reports whether list is a list of the scalar type elem.
+func isListOf(list, elem arrow.DataType) bool {
+	lt, ok := list.(arrow.ListLikeType)
+	return ok && !arrow.IsNested(elem.ID()) && arrow.TypeEqual(lt.Elem(), elem)
+}
+
+// mergeFrozen compares a field at nPath with the frozen schema, recording fields which
+// are not in it or have another type as conflicts instead of merging them.
+func (u *Bodkin) mergeFrozen(n *fieldPos, nPath, mergeAt []string) {
+	kin, err := u.old.getPath(nPath)
+	if err == ErrPathNotFound {
+		dotpath := "$" + strings.Join(nPath, ".")
+		u.conflicts.Set(dotpath, Field{
+			Dotpath:  dotpath,
+			Type:     n.field.Type.ID(),
+			dataType: n.field.Type,
+			Issue:    fmt.Errorf("%w %v : new field %v", ErrSchemaFrozen, dotpath, n.field.Type),
+		})
+		return
+	}
+	if kin.field.Type.ID() != n.field.Type.ID() || len(n.children) == 0 && !arrow.TypeEqual(kin.field.Type, n.field.Type) {
+		u.addConflict(kin, n, ErrSchemaFrozen)
+		return
+	}
+	for _, v := range n.childmap {
+		u.merge(v, mergeAt)
+	}
+}
+
 func (u *Bodkin) sortMapKeysDesc(k int) []string {
 	var m *omap.OrderedMap[string, *fieldPos]
 	var sortedPaths, paths []string
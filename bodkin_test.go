@@ -0,0 +1,205 @@
+package bodkin
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+// unifyTestInputs are nested inputs adding fields and changing types as they are unified.
+var unifyTestInputs = []string{
+	`{"id":1,"user":{"name":"a","tags":["x"]},"events":[{"kind":"click","at":1}]}`,
+	`{"id":2,"user":{"name":"b","age":30},"events":[{"kind":"view","at":2,"meta":{"ref":"r"}}]}`,
+	`{"id":3.5,"user":{"name":null,"tags":["y","z"]},"score":1.5}`,
+	`{"id":4,"user":{"name":"d","address":{"city":"c","zip":"z"}},"events":[]}`,
+}
+
+// dumpTree returns the dotpaths and types of the nodes of the tree rooted at f, failing
+// t if a node was allocated from fieldPosPool.
+func dumpTree(t *testing.T, f *fieldPos) string {
+	t.Helper()
+	var sb strings.Builder
+	var walk func(f *fieldPos)
+	walk = func(f *fieldPos) {
+		if f.pooled {
+			t.Errorf("retained node %s is pooled", f.dotPath())
+		}
+		fmt.Fprintf(&sb, "%s %v %v\n", f.dotPath(), f.arrowType, f.field)
+		for _, c := range f.children {
+			walk(c)
+		}
+	}
+	walk(f)
+	return sb.String()
+}
+
+func TestUnifyPooledNodesNotRetained(t *testing.T) {
+	u := NewBodkin()
+	for _, in := range unifyTestInputs {
+		if err := u.Unify(in); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old, original := dumpTree(t, u.old), dumpTree(t, u.original)
+
+	// inputs with known fields only reuse the pooled nodes released by earlier calls
+	for i := range 100 {
+		in := fmt.Sprintf(`{"id":%d,"user":{"name":"n%d","address":{"city":"c%d"}},"events":[{"kind":"k","at":%d}]}`, i, i, i, i)
+		if err := u.Unify(in); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := dumpTree(t, u.old); got != old {
+		t.Errorf("unified tree changed by reused pooled nodes:\n%s\nwant:\n%s", got, old)
+	}
+	if got := dumpTree(t, u.original); got != original {
+		t.Errorf("original tree changed by reused pooled nodes:\n%s\nwant:\n%s", got, original)
+	}
+}
+
+func TestUnifyAndRead(t *testing.T) {
+	input := `{"id":1,"name":"a","gone":null}
+{"id":2,"name":"b","gone":null}
+{"id":3,"name":"c","gone":null,"added":true}
+`
+	u := NewBodkin(WithIOReader(strings.NewReader(input), '\n'), WithNullColumnsAsNullType())
+	out := make(chan arrow.Record, 8)
+	err := u.UnifyAndRead(out, 2)
+	close(out)
+	if !errors.Is(err, ErrSchemaEvolved) {
+		t.Errorf("UnifyAndRead() = %v, want ErrSchemaEvolved", err)
+	}
+	rows := 0
+	for rec := range out {
+		if rec.NumCols() != 2 {
+			t.Errorf("record schema = %v, want the fields of the first datum", rec.Schema())
+		}
+		rows += int(rec.NumRows())
+		rec.Release()
+	}
+	if rows != 3 {
+		t.Errorf("read %d rows, want 3", rows)
+	}
+	// the frozen schema is built without resolving the null-only column into the tree
+	for _, c := range u.ChangeLog() {
+		if c.Kind == ErrFieldResolved {
+			t.Errorf("UnifyAndRead() resolved %s", c.Dotpath)
+		}
+	}
+}
+
+func BenchmarkUnify(b *testing.B) {
+	u := NewBodkin()
+	b.ReportAllocs()
+	for i := range b.N {
+		u.Unify(unifyTestInputs[i%len(unifyTestInputs)])
+	}
+}
+
+// BenchmarkUnifyBatch unifies the inputs of BenchmarkUnify in batches of 64, an op
+// being one datum as in BenchmarkUnify.
+func BenchmarkUnifyBatch(b *testing.B) {
+	batch := make([][]byte, 64)
+	for i := range batch {
+		batch[i] = []byte(unifyTestInputs[i%len(unifyTestInputs)])
+	}
+	u := NewBodkin()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i += len(batch) {
+		u.UnifyBatch(batch[:min(len(batch), b.N-i)])
+	}
+}
+
+func TestUnifyBatch(t *testing.T) {
+	batch := []string{
+		unifyTestInputs[0],
+		`{"a":`,              // invalid, must not consume the next datum
+		`{"b":1}`,            // completes the datum above in a shared stream
+		"",                   // empty
+		" \n",                // whitespace only
+		`7`,                  // not an object
+		`{"c":1} {"d":true}`, // trailing data, ignored as with Unify
+		unifyTestInputs[1],
+		"\t" + unifyTestInputs[2] + "\n",
+		unifyTestInputs[3],
+	}
+	// sorted, as the fields of objects are added in the random order of maps
+	want := NewBodkin(WithSortedFields())
+	for range 2 {
+		for _, in := range batch {
+			want.Unify(in)
+		}
+	}
+	data := make([][]byte, len(batch))
+	for i, in := range batch {
+		data[i] = []byte(in)
+	}
+	u := NewBodkin(WithSortedFields())
+	// the second batch reuses the decoder's scratch buffer
+	for range 2 {
+		if err := u.UnifyBatch(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if u.Count() != want.Count() {
+		t.Errorf("UnifyBatch() unified %d datum, want %d", u.Count(), want.Count())
+	}
+	got, err := u.Schema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ws, err := want.Schema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(ws) {
+		t.Errorf("UnifyBatch() schema =\n%v\nwant\n%v", got, ws)
+	}
+	if n := strings.Count(fmt.Sprint(u.err), "batch index"); n != 8 {
+		t.Errorf("UnifyBatch() accumulated %d errors, want 8:\n%v", n, u.err)
+	}
+}
+
+func TestFreeze(t *testing.T) {
+	u := NewBodkin()
+	if u.Freeze(); u.Frozen() {
+		t.Error("Freeze() froze a Bodkin without inputs")
+	}
+	if err := u.Unify(`{"a":1,"s":{"x":"y"}}`); err != nil {
+		t.Fatal(err)
+	}
+	frozen, err := u.Schema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Freeze(); !u.Frozen() {
+		t.Fatal("Frozen() = false after Freeze()")
+	}
+	if err := u.Unify(`{"a":"str","b":true,"s":{"x":"z","w":1}}`); err != nil {
+		t.Fatal(err)
+	}
+	if u.Count() != 1 {
+		t.Errorf("Count() = %d, want inputs counted once frozen", u.Count())
+	}
+	sc, err := u.Schema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sc.Equal(frozen) {
+		t.Errorf("Schema() = %v, want the frozen schema %v", sc, frozen)
+	}
+	conflicts := map[string]bool{}
+	for _, f := range u.Err() {
+		if errors.Is(f.Issue, ErrSchemaFrozen) {
+			conflicts[f.Dotpath] = true
+		}
+	}
+	for _, path := range []string{"$a", "$b", "$s.w"} {
+		if !conflicts[path] {
+			t.Errorf("Err() = %v, want a frozen schema conflict for %s", u.Err(), path)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package bodkin
+
+import (
+	"fmt"
+	"strings"
+)
+
+// canonicalKey returns the name under which key k of object m, a child of f, is merged
+// with WithCaseInsensitiveMerge: the first name seen at its path under case-folding,
+// ie. UserID then userid are both merged as UserID. If m has several keys differing
+// only by case, the one spelled as the canonical name is kept, or else the smallest,
+// and the others are recorded as conflicts wrapping ErrFieldNameCollision and skipped,
+// for which false is returned.
+func (f *fieldPos) canonicalKey(k string, m map[string]any) (string, bool) {
+	p := strings.ToLower(f.childPath(k))
+	canonical, ok := f.owner.canonicalNames[p]
+	if !ok {
+		canonical = k
+		f.owner.canonicalNames[p] = k
+	}
+	if winner := foldWinner(m, k, canonical); winner != k {
+		dotpath := f.childPath(k)
+		f.owner.conflicts.Set(dotpath, Field{
+			Dotpath: dotpath,
+			Issue:   fmt.Errorf("%w %v : with %v in the same object", ErrFieldNameCollision, dotpath, f.childPath(winner)),
+		})
+		return "", false
+	}
+	return canonical, true
+}
+
+// foldWinner returns the key of m equal to k under case-folding which is loaded: the
+// one spelled as canonical if there is one, otherwise the smallest.
+func foldWinner(m map[string]any, k, canonical string) string {
+	winner := k
+	for other := range m {
+		if other == winner || !strings.EqualFold(other, k) {
+			continue
+		}
+		if other == canonical || winner != canonical && other < winner {
+			winner = other
+		}
+	}
+	return winner
+}
@@ -0,0 +1,89 @@
+package bodkin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+// Change is a field addition or type conversion made to the unified schema, as
+// recorded in Changes().
+type Change struct {
+	Dotpath string
+	// Kind is ErrFieldAdded, ErrFieldTypeChanged, ErrFieldResolved or ErrFieldMaxDepth.
+	Kind error
+	// Type of the field after the change, nil if not known.
+	Type arrow.DataType
+	// Detail describes the change.
+	Detail string
+}
+
+// ChangeLog returns the changes recorded in Changes(), in the order they were made.
+func (u *Bodkin) ChangeLog() []Change { return u.changeLog }
+
+// addChange records a change of kind at dotpath.
+func (u *Bodkin) addChange(kind error, dotpath string, dt arrow.DataType, detail string) {
+	u.changes = errors.Join(u.changes, fmt.Errorf("%w %v : %v", kind, dotpath, detail))
+	u.changeLog = append(u.changeLog, Change{Dotpath: dotpath, Kind: kind, Type: dt, Detail: detail})
+}
+
+// MarshalJSON renders the change with its kind and type as readable strings.
+func (c Change) MarshalJSON() ([]byte, error) {
+	out := struct {
+		Dotpath string `json:"dotpath"`
+		Kind    string `json:"kind"`
+		Type    string `json:"arrow_type,omitempty"`
+		Detail  string `json:"detail,omitempty"`
+	}{Dotpath: c.Dotpath, Detail: c.Detail}
+	if c.Kind != nil {
+		out.Kind = c.Kind.Error()
+	}
+	if c.Type != nil {
+		out.Type = typeName(c.Type)
+	}
+	return json.Marshal(out)
+}
+
+// MarshalJSON renders the field with its Arrow type by name, ie. "int64" or
+// "list<struct>", rather than as the arrow.Type enum value.
+func (f Field) MarshalJSON() ([]byte, error) {
+	out := struct {
+		Dotpath string `json:"dotpath"`
+		Type    string `json:"arrow_type"`
+		Childen int    `json:"children,omitempty"`
+		Issue   string `json:"issue,omitempty"`
+		Pending string `json:"pending,omitempty"`
+	}{Dotpath: f.Dotpath, Childen: f.Childen}
+	out.Type = strings.ToLower(f.Type.String())
+	if f.dataType != nil {
+		out.Type = typeName(f.dataType)
+	}
+	if f.Issue != nil {
+		out.Issue = f.Issue.Error()
+	}
+	if f.Pending != NotPending {
+		out.Pending = f.Pending.String()
+	}
+	return json.Marshal(out)
+}
+
+// typeName returns a compact name of dt, naming the element types of lists and maps
+// but not the fields of structs.
+func typeName(dt arrow.DataType) string {
+	switch t := dt.(type) {
+	case *arrow.StructType:
+		return "struct"
+	case *arrow.MapType:
+		return "map<" + typeName(t.KeyType()) + "," + typeName(t.ItemType()) + ">"
+	case *arrow.FixedSizeListType:
+		return fmt.Sprintf("fixed_size_list<%s,%d>", typeName(t.Elem()), t.Len())
+	case arrow.ListLikeType:
+		return dt.Name() + "<" + typeName(t.Elem()) + ">"
+	case *arrow.DictionaryType:
+		return "dictionary<" + typeName(t.ValueType) + ">"
+	}
+	return dt.String()
+}
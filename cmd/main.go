@@ -22,11 +22,9 @@ func main() {
 			panic(err)
 		}
 		defer f.Close()
+		u = bodkin.NewBodkin(bodkin.WithInferTimeUnits(), bodkin.WithTypeConversion(), bodkin.WithScanBufferSize(1024*1024*64))
 		s := bufio.NewScanner(f)
-		u = bodkin.NewBodkin(bodkin.WithInferTimeUnits(), bodkin.WithTypeConversion())
-		if err != nil {
-			panic(err)
-		}
+		s.Buffer(make([]byte, 0, 1024*64), u.ScanBufferSize())
 
 		for s.Scan() {
 			err = u.Unify(s.Bytes())
@@ -45,6 +43,19 @@ func main() {
 		if err != nil {
 			panic(err)
 		}
+		// resume schema evolution from the imported schema
+		u, err = bodkin.FromArrowSchema(schema, u.Opts()...)
+		if err != nil {
+			panic(err)
+		}
+		err = u.Unify(jsonS1)
+		if err != nil {
+			panic(err)
+		}
+		schema, err = u.Schema()
+		if err != nil {
+			panic(err)
+		}
 		ff, err := os.Open(filepath)
 		if err != nil {
 			panic(err)
@@ -0,0 +1,64 @@
+package bodkin
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+// EnumKey is the Arrow field metadata key of the distinct values captured with
+// WithCaptureEnums.
+const EnumKey = "enum"
+
+// enumSet is the set of distinct string values seen at a path, nil once more than
+// the cap set with WithCaptureEnums were seen.
+type enumSet struct {
+	values map[string]bool
+}
+
+// observeEnum adds string value s to the distinct values seen at dotpath, abandoning
+// the capture if there are more than the cap.
+func (u *Bodkin) observeEnum(dotpath, s string) {
+	e, ok := u.enums[dotpath]
+	if !ok {
+		e = &enumSet{values: make(map[string]bool)}
+		u.enums[dotpath] = e
+	}
+	if e.values == nil || e.values[s] {
+		return
+	}
+	if len(e.values) == u.maxEnumValues {
+		e.values = nil
+		return
+	}
+	e.values[s] = true
+}
+
+// tagEnum returns string field with the sorted distinct values seen at the path of f
+// added to its metadata, comma separated.
+func (f *fieldPos) tagEnum(field arrow.Field) arrow.Field {
+	if f.owner.maxEnumValues <= 0 {
+		return field
+	}
+	t := field.Type
+	if lt, ok := t.(arrow.ListLikeType); ok {
+		t = lt.Elem()
+	}
+	if t.ID() != arrow.STRING && t.ID() != arrow.LARGE_STRING {
+		return field
+	}
+	e, ok := f.owner.enums[f.dotPath()]
+	if !ok || len(e.values) == 0 {
+		return field
+	}
+	values := make([]string, 0, len(e.values))
+	for v := range e.values {
+		values = append(values, v)
+	}
+	slices.Sort(values)
+	keys := slices.Concat(field.Metadata.Keys(), []string{EnumKey})
+	vals := slices.Concat(field.Metadata.Values(), []string{strings.Join(values, ",")})
+	field.Metadata = arrow.NewMetadata(keys, vals)
+	return field
+}
@@ -0,0 +1,40 @@
+package bodkin
+
+import (
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/loicalleyne/bodkin/reader"
+)
+
+// ExtraColumn is the name of the map column holding the top-level fields diverted
+// from the schema by WithMaxFieldCount.
+const ExtraColumn = "_extra"
+
+// divert reports whether the new top-level field k is diverted into the extra column
+// because the schema reached the number of fields set with WithMaxFieldCount, noting
+// it in the owner's changes the first time. An input field named ExtraColumn is always
+// diverted, so that it is kept in the extra column rather than colliding with it.
+func (u *Bodkin) divert(k string) bool {
+	if u.maxFieldCount <= 0 {
+		return false
+	}
+	dotpath := "$" + k
+	if _, ok := u.knownFields.Get(dotpath); ok {
+		return false
+	}
+	if u.diverted[k] {
+		return true
+	}
+	if u.knownFields.Len() < u.maxFieldCount && k != ExtraColumn {
+		return false
+	}
+	u.diverted[k] = true
+	u.addChange(ErrFieldDiverted, dotpath, nil, "into "+ExtraColumn)
+	return true
+}
+
+// extraField returns the map column of the diverted fields, whose values are loaded
+// as strings, JSON for objects and arrays.
+func (u *Bodkin) extraField() arrow.Field {
+	md := arrow.NewMetadata([]string{reader.ExtraColumnKey}, []string{"true"})
+	return buildArrowField(ExtraColumn, arrow.MapOf(arrow.BinaryTypes.String, u.stringType()), md, true)
+}
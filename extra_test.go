@@ -0,0 +1,42 @@
+package bodkin
+
+import (
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow/array"
+)
+
+func TestMaxFieldCountInputExtraKey(t *testing.T) {
+	input := `{"a":1,"b":2,"_extra":"mine","c":3}`
+	u := NewBodkin(WithMaxFieldCount(2))
+	if err := u.Unify(input); err != nil {
+		t.Fatal(err)
+	}
+	sc, err := u.Schema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(sc.FieldIndices(ExtraColumn)); got != 1 {
+		t.Fatalf("schema has %d %s fields, want 1: %v", got, ExtraColumn, sc)
+	}
+	r, err := u.NewReader()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Release()
+	rec, err := r.ReadToRecord([]byte(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rec.Release()
+	m := rec.Column(sc.FieldIndices(ExtraColumn)[0]).(*array.Map)
+	keys, items := m.Keys().(*array.String), m.Items().(*array.String)
+	got := make(map[string]string)
+	for i := 0; i < keys.Len(); i++ {
+		got[keys.Value(i)] = items.Value(i)
+	}
+	// the input's own _extra key is kept along with the keys diverted from the schema
+	if got["_extra"] != "mine" || len(got)+len(sc.Fields())-1 != 4 {
+		t.Errorf("%s loaded as %v, want _extra and the keys not in %v", ExtraColumn, got, sc)
+	}
+}
@@ -0,0 +1,98 @@
+package bodkin
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"slices"
+	"strings"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+// SchemaFingerprint returns the hex encoded SHA-256 digest of a canonical form of the
+// unified schema, to detect schema changes or key a schema registry.
+//
+// Fields are sorted by name at every level of nesting and metadata by key, so the
+// fingerprint doesn't depend on the order in which fields were first seen: Bodkins
+// which converge to the same schema have the same fingerprint.
+func (u *Bodkin) SchemaFingerprint() (string, error) {
+	sc, err := u.Schema()
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	writeFields(h, sc.Fields())
+	writeMetadata(h, sc.Metadata())
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// writeFields writes the canonical form of fields, sorted by name, to h.
+func writeFields(h hash.Hash, fields []arrow.Field) {
+	fields = slices.SortedFunc(slices.Values(fields), func(a, b arrow.Field) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	h.Write([]byte{'{'})
+	for _, f := range fields {
+		writeField(h, f)
+	}
+	h.Write([]byte{'}'})
+}
+
+func writeField(h hash.Hash, f arrow.Field) {
+	// names are quoted so that no name can be mistaken for the delimiters
+	fmt.Fprintf(h, "%q:", f.Name)
+	writeType(h, f.Type)
+	if f.Nullable {
+		h.Write([]byte{'?'})
+	}
+	writeMetadata(h, f.Metadata)
+	h.Write([]byte{';'})
+}
+
+func writeType(h hash.Hash, dt arrow.DataType) {
+	switch t := dt.(type) {
+	case *arrow.StructType:
+		h.Write([]byte("struct"))
+		writeFields(h, t.Fields())
+	case *arrow.MapType:
+		fmt.Fprintf(h, "map<%v,", t.KeysSorted)
+		writeField(h, t.KeyField())
+		writeField(h, t.ItemField())
+		h.Write([]byte{'>'})
+	case *arrow.FixedSizeListType:
+		fmt.Fprintf(h, "fixed_size_list<%d,", t.Len())
+		writeField(h, t.ElemField())
+		h.Write([]byte{'>'})
+	case arrow.ListLikeType:
+		fmt.Fprintf(h, "%s<", dt.Name())
+		writeField(h, t.ElemField())
+		h.Write([]byte{'>'})
+	case *arrow.DictionaryType:
+		fmt.Fprintf(h, "dictionary<%v,%v,", t.IndexType, t.Ordered)
+		writeType(h, t.ValueType)
+		h.Write([]byte{'>'})
+	default:
+		h.Write([]byte(dt.String()))
+	}
+}
+
+// writeMetadata writes metadata, sorted by key, to h.
+func writeMetadata(h hash.Hash, md arrow.Metadata) {
+	if md.Len() == 0 {
+		return
+	}
+	idx := make([]int, md.Len())
+	for i := range idx {
+		idx[i] = i
+	}
+	slices.SortFunc(idx, func(a, b int) int {
+		return strings.Compare(md.Keys()[a], md.Keys()[b])
+	})
+	h.Write([]byte{'['})
+	for _, i := range idx {
+		fmt.Fprintf(h, "%q=%q;", md.Keys()[i], md.Values()[i])
+	}
+	h.Write([]byte{']'})
+}
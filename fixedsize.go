@@ -0,0 +1,35 @@
+package bodkin
+
+import (
+	"fmt"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+// observeListLen records the length n of an array at dotpath for WithInferFixedSizeList.
+// A path whose arrays vary in length is marked with -1, noting the change to a list
+// in the owner's changes if it was previously of a fixed size.
+func (u *Bodkin) observeListLen(dotpath string, n int) {
+	l, ok := u.listLens[dotpath]
+	switch {
+	case !ok:
+		u.listLens[dotpath] = n
+	case l >= 0 && l != n:
+		u.listLens[dotpath] = -1
+		if l > 0 {
+			u.addChange(ErrFieldTypeChanged, dotpath, nil, fmt.Sprintf("from fixed size list of %d to list, length %d", l, n))
+		}
+	}
+}
+
+// fixedSizeList returns the fixed size list type of elem if every array seen at the
+// path of f had the same length, or nil.
+func (f *fieldPos) fixedSizeList(elem arrow.DataType) arrow.DataType {
+	if !f.owner.inferFixedSizeList {
+		return nil
+	}
+	if n := f.owner.listLens[f.dotPath()]; n > 0 {
+		return arrow.FixedSizeListOf(int32(n), elem)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package bodkin
+
+import (
+	"fmt"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/loicalleyne/bodkin/reader"
+)
+
+// FromArrowSchema returns a new Bodkin created with opts whose unified schema is s,
+// ie. a schema exported with ExportSchemaFile, so that unification can resume against
+// new inputs without the original samples. It is the inverse of Schema(): nested structs,
+// lists and maps are reconstructed, fixed size lists become lists of their element type
+// and field names changed by a name sanitizer are restored from their metadata.
+func FromArrowSchema(s *arrow.Schema, opts ...Option) (*Bodkin, error) {
+	if s == nil {
+		return nil, fmt.Errorf("nil schema")
+	}
+	u := newBodkin(opts...)
+	original := newFieldPos(u)
+	if err := schemaToFieldPos(original, s.Fields()); err != nil {
+		return nil, err
+	}
+	u.original = original
+	old := newFieldPos(u)
+	// Building the tree again registers its fields as known fields.
+	if err := schemaToFieldPos(old, s.Fields()); err != nil {
+		return nil, err
+	}
+	u.old = old
+	return u, nil
+}
+
+// schemaToFieldPos adds a child to f for each of fields.
+func schemaToFieldPos(f *fieldPos, fields []arrow.Field) error {
+	for _, field := range fields {
+		name := field.Name
+		if orig, ok := field.Metadata.GetValue(reader.OriginalNameKey); ok {
+			name = orig
+		}
+		if _, ok := f.childmap[name]; ok {
+			return fmt.Errorf("%v : duplicate field %s", ErrInvalidInput, name)
+		}
+		child := f.newChild(name)
+		if err := arrowToFieldPos(child, field); err != nil {
+			return err
+		}
+		f.assignChild(child)
+	}
+	var fs []arrow.Field
+	for _, c := range f.children {
+		fs = append(fs, c.field)
+	}
+	f.arrowType = arrow.STRUCT
+	f.field = arrow.Field{Name: f.name, Type: arrow.StructOf(fs...), Metadata: f.field.Metadata, Nullable: true}
+	return nil
+}
+
+// arrowToFieldPos sets the type of f to that of field, adding the children of
+// nested types.
+func arrowToFieldPos(f *fieldPos, field arrow.Field) error {
+	field.Nullable = true
+	f.field = field
+	f.arrowType = field.Type.ID()
+	if _, ok := field.Metadata.GetValue(reader.JSONBlobKey); ok {
+		f.jsonBlob = true
+		return nil
+	}
+	switch t := field.Type.(type) {
+	case *arrow.StructType:
+		f.isStruct = true
+		return schemaToFieldPos(f, t.Fields())
+	case *arrow.FixedSizeListType:
+		if f.owner.inferFixedSizeList {
+			f.owner.listLens[f.dotPath()] = int(t.Len())
+		}
+		f.field.Type = f.owner.listOf(t.Elem())
+		f.arrowType = f.field.Type.ID()
+		return listElemToFieldPos(f, t.Elem())
+	case *arrow.ListType:
+		return listElemToFieldPos(f, t.Elem())
+	case *arrow.LargeListType:
+		return listElemToFieldPos(f, t.Elem())
+	case *arrow.MapType:
+		f.isMap = true
+	}
+	return nil
+}
+
+// listElemToFieldPos marks f as a list of elem, adding an element child for nested
+// element types as evaluating a JSON array would.
+func listElemToFieldPos(f *fieldPos, elem arrow.DataType) error {
+	f.isList = true
+	switch elem.ID() {
+	case arrow.STRUCT, arrow.LIST, arrow.LARGE_LIST, arrow.FIXED_SIZE_LIST:
+		child := f.newChild(f.name + ".elem")
+		if err := arrowToFieldPos(child, arrow.Field{Name: child.name, Type: elem, Nullable: true}); err != nil {
+			return err
+		}
+		f.assignChild(child)
+		if elem.ID() == arrow.FIXED_SIZE_LIST {
+			f.field.Type = f.owner.listOf(child.field.Type)
+		}
+	}
+	return nil
+}
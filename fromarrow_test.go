@@ -0,0 +1,71 @@
+package bodkin
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+func TestFromArrowSchema(t *testing.T) {
+	sanitize := func(s string) string { return strings.ReplaceAll(s, "-", "_") }
+	u := NewBodkin(WithNameSanitizer(sanitize))
+	if err := u.Unify(`{"id":1,"user-name":"a","tags":["x"],"events":[{"kind":"click","meta":{"ref":"r"}}]}`); err != nil {
+		t.Fatal(err)
+	}
+	want, err := u.Schema()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := FromArrowSchema(want, WithNameSanitizer(sanitize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sc, err := r.Schema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sc.Equal(want) {
+		t.Errorf("Schema() = %v, want %v", sc, want)
+	}
+	// unification resumes: known fields are unchanged, only new ones are added
+	if err := r.Unify(`{"id":2,"user-name":"b","score":1.5,"events":[{"kind":"view","at":3,"meta":{"ref":"s"}}]}`); err != nil {
+		t.Fatal(err)
+	}
+	var added []string
+	for _, c := range r.ChangeLog() {
+		if !errors.Is(c.Kind, ErrFieldAdded) {
+			t.Errorf("ChangeLog() = %v, want only field additions", r.ChangeLog())
+		}
+		added = append(added, c.Dotpath)
+	}
+	slices.Sort(added)
+	if got := strings.Join(added, ","); got != "$events.events.elem.at,$score" {
+		t.Errorf("added fields = %s, want $events.events.elem.at and $score", got)
+	}
+	sc, err = r.Schema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"id", "user_name", "tags", "events", "score"} {
+		if _, ok := sc.FieldsByName(name); !ok {
+			t.Errorf("Schema() = %v, want field %s", sc, name)
+		}
+	}
+}
+
+func TestFromArrowSchemaErrors(t *testing.T) {
+	if _, err := FromArrowSchema(nil); err == nil {
+		t.Error("FromArrowSchema(nil) succeeded")
+	}
+	dup := arrow.NewSchema([]arrow.Field{
+		{Name: "a", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "a", Type: arrow.BinaryTypes.String},
+	}, nil)
+	if _, err := FromArrowSchema(dup); err == nil || !strings.Contains(err.Error(), "duplicate field a") {
+		t.Errorf("FromArrowSchema() = %v, want a duplicate field error", err)
+	}
+}
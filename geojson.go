@@ -0,0 +1,116 @@
+package bodkin
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+// GeoTypeKey is the Arrow field metadata key of GeoJSON geometry types set with
+// WithGeoJSONDetection.
+const GeoTypeKey = "geotype"
+
+// geoJSONDepths are the GeoJSON geometry types, with the depth at which positions are
+// found in their coordinates.
+var geoJSONDepths = map[string]int{
+	"Point":           0,
+	"MultiPoint":      1,
+	"LineString":      1,
+	"MultiLineString": 2,
+	"Polygon":         2,
+	"MultiPolygon":    3,
+}
+
+// geoShape is the geometry type and number of dimensions of the positions of the
+// GeoJSON geometries seen at a path.
+type geoShape struct {
+	geoType string
+	// -1 if geometries of several types were seen
+	depth int
+	// 0 if not known, -1 if positions of several dimensions were seen
+	dims int
+}
+
+// observeGeoJSON records the geometry type of object m at dotpath if it is a GeoJSON
+// geometry, ie. {"type":"Point","coordinates":[1.0,2.0]}.
+func (u *Bodkin) observeGeoJSON(dotpath string, m map[string]any) {
+	t, _ := m["type"].(string)
+	depth, ok := geoJSONDepths[t]
+	if !ok {
+		return
+	}
+	coords, ok := m["coordinates"].([]any)
+	if !ok {
+		return
+	}
+	dims := positionDims(coords, depth)
+	g, ok := u.geoShapes[dotpath]
+	if !ok {
+		u.geoShapes[dotpath] = &geoShape{geoType: strings.ToLower(t), depth: depth, dims: dims}
+		return
+	}
+	if g.geoType != strings.ToLower(t) {
+		g.geoType, g.depth = "geometry", -1
+	}
+	switch {
+	case dims == 0:
+	case g.dims == 0:
+		g.dims = dims
+	case g.dims != dims:
+		g.dims = -1
+	}
+}
+
+// positionDims returns the number of dimensions of the first position found in coords
+// at depth, 0 if there is none, or -1 if it is not a position.
+func positionDims(coords []any, depth int) int {
+	for range depth {
+		if len(coords) == 0 {
+			return 0
+		}
+		next, ok := coords[0].([]any)
+		if !ok {
+			return -1
+		}
+		coords = next
+	}
+	if len(coords) < 2 {
+		return -1
+	}
+	for _, v := range coords {
+		if isNested(v) || v == nil {
+			return -1
+		}
+	}
+	return len(coords)
+}
+
+// tagGeoJSON returns the struct field of f with its GeoJSON geometry type added to its
+// metadata. If every geometry seen at the path of f had the same type and dimensions,
+// its coordinates are normalized to fixed size lists of float64 positions.
+func (f *fieldPos) tagGeoJSON(field arrow.Field) arrow.Field {
+	g, ok := f.owner.geoShapes[f.dotPath()]
+	if !ok {
+		return field
+	}
+	keys := slices.Concat(field.Metadata.Keys(), []string{GeoTypeKey})
+	values := slices.Concat(field.Metadata.Values(), []string{g.geoType})
+	field.Metadata = arrow.NewMetadata(keys, values)
+	st, ok := field.Type.(*arrow.StructType)
+	if !ok || g.depth < 0 || g.dims <= 0 {
+		return field
+	}
+	var coords arrow.DataType = arrow.FixedSizeListOf(int32(g.dims), arrow.PrimitiveTypes.Float64)
+	for range g.depth {
+		coords = f.owner.listOf(coords)
+	}
+	fields := st.Fields()
+	for i := range fields {
+		if fields[i].Name == "coordinates" {
+			fields[i].Type = coords
+		}
+	}
+	field.Type = arrow.StructOf(fields...)
+	return field
+}
@@ -0,0 +1,158 @@
+package bodkin
+
+import (
+	"fmt"
+	"go/format"
+	"go/token"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/loicalleyne/bodkin/reader"
+)
+
+// ExportGoStruct returns the Go source of a struct type named typeName matching the
+// unified schema, which decodes the JSON inputs with encoding/json and DecodeInto.
+//
+// Fields are exported and tagged with their input names, nullable scalar and struct
+// fields are pointers, nested structs are types named after their parent type and
+// field, lists are slices and maps are Go maps. Timestamps, dates and durations are
+// strings, since their inputs may not be in a layout encoding/json decodes to time.Time
+// or time.Duration; decimals are json.Number and JSON blob fields json.RawMessage. The
+// source has no package clause or imports, so "encoding/json" must be imported where it
+// is used if it is referenced.
+func (u *Bodkin) ExportGoStruct(typeName string) (string, error) {
+	if !token.IsIdentifier(typeName) {
+		return "", fmt.Errorf("invalid go type name %q", typeName)
+	}
+	sc, err := u.Schema()
+	if err != nil {
+		return "", err
+	}
+	g := &goStructGen{used: map[string]bool{typeName: true}}
+	g.structType(typeName, sc.Fields())
+	src, err := format.Source([]byte(g.sb.String()))
+	if err != nil {
+		return "", fmt.Errorf("format go struct : %w", err)
+	}
+	return string(src), nil
+}
+
+// goStructGen writes the type declarations of a Go struct and its nested structs.
+type goStructGen struct {
+	sb strings.Builder
+	// type names declared
+	used map[string]bool
+	// nested structs to declare after the current one
+	pending []goStruct
+}
+
+type goStruct struct {
+	name   string
+	fields []arrow.Field
+}
+
+// structType writes the declaration of the struct named name with fields, followed by
+// those of its nested structs.
+func (g *goStructGen) structType(name string, fields []arrow.Field) {
+	fmt.Fprintf(&g.sb, "type %s struct {\n", name)
+	names := make(map[string]bool)
+	for _, f := range fields {
+		fieldName := uniqueName(goFieldName(f.Name), names)
+		tag := f.Name
+		if orig, ok := f.Metadata.GetValue(reader.OriginalNameKey); ok {
+			tag = orig
+		}
+		t := g.goType(name+fieldName, f)
+		fmt.Fprintf(&g.sb, "\t%s %s `json:%s`\n", fieldName, t, strconv.Quote(tag))
+	}
+	g.sb.WriteString("}\n\n")
+	for len(g.pending) > 0 {
+		s := g.pending[0]
+		g.pending = g.pending[1:]
+		g.structType(s.name, s.fields)
+	}
+}
+
+// goType returns the Go type of field f, name is used to name nested structs.
+func (g *goStructGen) goType(name string, f arrow.Field) string {
+	if _, ok := f.Metadata.GetValue(reader.JSONBlobKey); ok {
+		return "json.RawMessage"
+	}
+	t := g.goValueType(name, f.Type)
+	if !f.Nullable || strings.HasPrefix(t, "[]") || strings.HasPrefix(t, "map[") || t == "any" || t == "json.RawMessage" {
+		return t
+	}
+	return "*" + t
+}
+
+// goValueType returns the Go type of the values of dt, name is used to name nested structs.
+func (g *goStructGen) goValueType(name string, dt arrow.DataType) string {
+	switch t := dt.(type) {
+	case *arrow.StructType:
+		name = uniqueName(name, g.used)
+		g.pending = append(g.pending, goStruct{name: name, fields: t.Fields()})
+		return name
+	case *arrow.MapType:
+		return "map[" + g.goValueType(name+"Key", t.KeyType()) + "]" + g.goValueType(name+"Value", t.ItemType())
+	case arrow.ListLikeType:
+		return "[]" + g.goValueType(name+"Elem", t.Elem())
+	case *arrow.RunEndEncodedType:
+		return g.goValueType(name, t.Encoded())
+	case *arrow.DictionaryType:
+		return g.goValueType(name, t.ValueType)
+	case arrow.ExtensionType:
+		return g.goValueType(name, t.StorageType())
+	}
+	switch dt.ID() {
+	case arrow.BOOL:
+		return "bool"
+	case arrow.INT8, arrow.INT16, arrow.INT32, arrow.INT64,
+		arrow.UINT8, arrow.UINT16, arrow.UINT32, arrow.UINT64, arrow.FLOAT32, arrow.FLOAT64:
+		return strings.ToLower(dt.ID().String())
+	case arrow.FLOAT16:
+		return "float32"
+	case arrow.STRING, arrow.LARGE_STRING, arrow.TIME32, arrow.TIME64,
+		arrow.TIMESTAMP, arrow.DATE32, arrow.DATE64, arrow.DURATION:
+		return "string"
+	case arrow.BINARY, arrow.LARGE_BINARY, arrow.FIXED_SIZE_BINARY:
+		return "[]byte"
+	case arrow.DECIMAL128, arrow.DECIMAL256:
+		return "json.Number"
+	}
+	return "any"
+}
+
+// goFieldName returns name as an exported Go identifier, ie. "user_id" becomes "UserId".
+func goFieldName(name string) string {
+	var sb strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		sb.WriteRune(r)
+	}
+	s := sb.String()
+	if s == "" || !unicode.IsUpper([]rune(s)[0]) {
+		s = "F" + s
+	}
+	return s
+}
+
+// uniqueName returns name, or name followed by the first number making it unique in used,
+// and adds it to used.
+func uniqueName(name string, used map[string]bool) string {
+	unique := name
+	for i := 2; used[unique]; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+	used[unique] = true
+	return unique
+}
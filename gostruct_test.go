@@ -0,0 +1,37 @@
+package bodkin
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+func TestExportGoStructTimeFieldsAreStrings(t *testing.T) {
+	u := NewBodkin(WithInferTimeUnits(), WithISODurations(arrow.Second))
+	if err := u.Unify(`{"d":"2024-01-02","ts":"2024-01-02 15:04:05","dur":"PT1H"}`); err != nil {
+		t.Fatal(err)
+	}
+	sc, err := u.Schema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range sc.Fields() {
+		if f.Type.ID() == arrow.STRING {
+			t.Fatalf("field %s inferred as %v", f.Name, f.Type)
+		}
+	}
+	src, err := u.ExportGoStruct("Row")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(src, "time.") {
+		t.Errorf("ExportGoStruct() references package time:\n%s", src)
+	}
+	for _, field := range []string{"D", "Ts", "Dur"} {
+		if !regexp.MustCompile(`\t` + field + `\s+\*string\s`).MatchString(src) {
+			t.Errorf("field %s is not a string:\n%s", field, src)
+		}
+	}
+}
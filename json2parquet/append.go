@@ -0,0 +1,182 @@
+package json2parquet
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet/file"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	"github.com/loicalleyne/bodkin"
+	"github.com/loicalleyne/bodkin/pq"
+)
+
+var ErrIncompatibleSchema = errors.New("incompatible schema")
+
+// AppendRecordsToFile appends the newline-delimited JSON in inputFile to the Parquet
+// file existingParquet, returning the number of rows appended.
+//
+// The schema of inputFile is unified with opts and must be compatible with the Arrow
+// schema stored in existingParquet: every column must exist in the file with the same
+// type, or a type it can be loaded as (integers as wider integers or floats). Columns of
+// the file missing from the input are written as nulls. If the schemas are not
+// compatible, an error wrapping ErrIncompatibleSchema lists the conflicting columns.
+//
+// Parquet files can't be appended to in place: the existing rows and the new rows are
+// written to a temporary file in the same directory which then replaces existingParquet,
+// so the file is left untouched if an error occurs.
+func AppendRecordsToFile(inputFile, existingParquet string, opts ...bodkin.Option) (int, error) {
+	existing, err := parquetSchema(existingParquet)
+	if err != nil {
+		return 0, err
+	}
+	u, err := UnifyFromFile(inputFile, opts...)
+	if err != nil {
+		return 0, err
+	}
+	incoming, err := u.Schema()
+	if err != nil {
+		return 0, err
+	}
+	if conflicts := schemaConflicts("", existing.Fields(), incoming.Fields()); len(conflicts) > 0 {
+		return 0, fmt.Errorf("%w : %s", ErrIncompatibleSchema, strings.Join(conflicts, "; "))
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(existingParquet), filepath.Base(existingParquet)+".*.tmp")
+	if err != nil {
+		return 0, err
+	}
+	tmpPath := tmp.Name()
+	tmp.Close()
+	defer os.Remove(tmpPath)
+
+	pw, _, err := pq.NewParquetWriter(existing, pq.DefaultWrtp, tmpPath)
+	if err != nil {
+		return 0, err
+	}
+	if err := copyParquet(pw, existingParquet); err != nil {
+		pw.Close()
+		return 0, err
+	}
+	f, err := os.Open(inputFile)
+	if err != nil {
+		pw.Close()
+		return 0, err
+	}
+	defer f.Close()
+	n, _, err := writeLines(pw, existing, bufio.NewReaderSize(f, 1024*1024*128), nil)
+	if err != nil {
+		pw.Close()
+		return 0, err
+	}
+	if err := pw.Close(); err != nil {
+		return 0, err
+	}
+	if err := os.Rename(tmpPath, existingParquet); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// parquetSchema returns the Arrow schema of the Parquet file at path.
+func parquetSchema(path string) (*arrow.Schema, error) {
+	rdr, err := file.OpenParquetFile(path, false)
+	if err != nil {
+		return nil, err
+	}
+	defer rdr.Close()
+	fr, err := pqarrow.NewFileReader(rdr, pqarrow.ArrowReadProperties{}, memory.DefaultAllocator)
+	if err != nil {
+		return nil, err
+	}
+	return fr.Schema()
+}
+
+// copyParquet writes the records of the Parquet file at path to pw.
+func copyParquet(pw *pq.ParquetWriter, path string) error {
+	rdr, err := file.OpenParquetFile(path, false)
+	if err != nil {
+		return err
+	}
+	defer rdr.Close()
+	fr, err := pqarrow.NewFileReader(rdr, pqarrow.ArrowReadProperties{BatchSize: 1024 * 64}, memory.DefaultAllocator)
+	if err != nil {
+		return err
+	}
+	rr, err := fr.GetRecordReader(context.Background(), nil, nil)
+	if err != nil {
+		return err
+	}
+	defer rr.Release()
+	for rr.Next() {
+		if err := pw.WriteRecord(rr.Record()); err != nil {
+			return err
+		}
+	}
+	// the record reader reports the end of the file as io.EOF
+	if err := rr.Err(); !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
+
+// schemaConflicts returns a description of each incoming field which can't be loaded
+// into the existing fields.
+func schemaConflicts(prefix string, existing, incoming []arrow.Field) []string {
+	var conflicts []string
+	for _, in := range incoming {
+		path := prefix + in.Name
+		i := -1
+		for j, ex := range existing {
+			if ex.Name == in.Name {
+				i = j
+				break
+			}
+		}
+		if i < 0 {
+			conflicts = append(conflicts, fmt.Sprintf("%s : not in existing schema", path))
+			continue
+		}
+		conflicts = append(conflicts, typeConflicts(path, existing[i].Type, in.Type)...)
+	}
+	return conflicts
+}
+
+func typeConflicts(path string, ex, in arrow.DataType) []string {
+	switch ext := ex.(type) {
+	case *arrow.StructType:
+		if ins, ok := in.(*arrow.StructType); ok {
+			return schemaConflicts(path+".", ext.Fields(), ins.Fields())
+		}
+	case arrow.ListLikeType:
+		if inl, ok := in.(arrow.ListLikeType); ok && ex.ID() == in.ID() {
+			return typeConflicts(path+".elem", ext.Elem(), inl.Elem())
+		}
+	default:
+		if arrow.TypeEqual(ex, in) || loadableAs(ex, in) {
+			return nil
+		}
+	}
+	return []string{fmt.Sprintf("%s : existing %v, new %v", path, ex, in)}
+}
+
+// loadableAs reports whether values of type in can be loaded into a column of type ex.
+func loadableAs(ex, in arrow.DataType) bool {
+	if !arrow.IsInteger(in.ID()) {
+		return false
+	}
+	switch ex.ID() {
+	case arrow.FLOAT32, arrow.FLOAT64:
+		return true
+	case arrow.INT64:
+		return in.ID() != arrow.UINT64
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package json2parquet
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet/file"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	"github.com/loicalleyne/bodkin/pq"
+)
+
+// readRows returns the rows of the Parquet file at path as their column values
+// joined by spaces, and the file's column names.
+func readRows(t *testing.T, path string) ([]string, []string) {
+	t.Helper()
+	rdr, err := file.OpenParquetFile(path, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rdr.Close()
+	fr, err := pqarrow.NewFileReader(rdr, pqarrow.ArrowReadProperties{}, memory.DefaultAllocator)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tbl, err := fr.ReadTable(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tbl.Release()
+	var names []string
+	for _, f := range tbl.Schema().Fields() {
+		names = append(names, f.Name)
+	}
+	tr := array.NewTableReader(tbl, -1)
+	defer tr.Release()
+	var rows []string
+	for tr.Next() {
+		rec := tr.Record()
+		for i := 0; i < int(rec.NumRows()); i++ {
+			var vals []string
+			for _, col := range rec.Columns() {
+				vals = append(vals, col.ValueStr(i))
+			}
+			rows = append(rows, strings.Join(vals, " "))
+		}
+	}
+	return rows, names
+}
+
+// writeFile writes content to name in dir and returns its path.
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestAppendRecordsToFile(t *testing.T) {
+	dir := t.TempDir()
+	sc := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+		{Name: "name", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "score", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+	}, nil)
+	existing := filepath.Join(dir, "existing.parquet")
+	pw, _, err := pq.NewParquetWriter(sc, pq.DefaultWrtp, existing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, row := range []string{`{"id":1,"name":"a","score":0.5}`, `{"id":2,"name":"b","score":1.5}`} {
+		if err := pw.Write([]byte(row)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := pw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// score is loaded from integers, the columns missing from the input are null
+	input := writeFile(t, dir, "input.json", "{\"id\":3,\"score\":2}\n{\"id\":4,\"score\":3}\n")
+	n, err := AppendRecordsToFile(input, existing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("AppendRecordsToFile() = %d, want 2", n)
+	}
+	want := []string{"1 a 0.5", "2 b 1.5", "3 (null) 2", "4 (null) 3"}
+	if rows, _ := readRows(t, existing); strings.Join(rows, "|") != strings.Join(want, "|") {
+		t.Errorf("rows = %q, want %q", rows, want)
+	}
+
+	bad := writeFile(t, dir, "bad.json", "{\"id\":\"x\",\"extra\":true}\n")
+	if _, err := AppendRecordsToFile(bad, existing); !errors.Is(err, ErrIncompatibleSchema) {
+		t.Fatalf("AppendRecordsToFile() = %v, want ErrIncompatibleSchema", err)
+	} else if !strings.Contains(err.Error(), "id :") || !strings.Contains(err.Error(), "extra :") {
+		t.Errorf("AppendRecordsToFile() = %v, want the id and extra conflicts", err)
+	}
+	if rows, _ := readRows(t, existing); strings.Join(rows, "|") != strings.Join(want, "|") {
+		t.Errorf("rows after a failed append = %q, want %q", rows, want)
+	}
+	if tmps, _ := filepath.Glob(filepath.Join(dir, "*.tmp")); len(tmps) != 0 {
+		t.Errorf("temporary files left: %v", tmps)
+	}
+}
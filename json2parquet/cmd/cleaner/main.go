@@ -68,6 +68,7 @@ func main() {
 
 	r := bufio.NewReaderSize(f, 1024*4)
 	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, 1024*64), 1024*1024*64)
 	newline := []byte("\n")
 	for s.Scan() {
 		y := s.Bytes()
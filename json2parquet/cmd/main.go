@@ -19,8 +19,10 @@ func main() {
 	withTypeConversion := flag.Bool("type_conversion", false, "upgrade field types if data changes")
 	inputFile := flag.String("in", "s.json", "input file")
 	outputFile := flag.String("out", "t.parquet", "output file")
+	deadLetterFile := flag.String("deadletter", "", "file to write rows that fail conversion to; if empty the conversion stops at the first error")
 	dryRun := flag.Bool("n", false, "only print the schema")
 	lines := flag.Int("lines", 0, "number of lines from which to infer schema; 0 means whole file is scanned")
+	schemaOut := flag.String("schema-out", "", "file to write a JSON description of the schema and its changes to, ie. t.schema.json")
 	flag.Parse()
 	if *inputFile == "" {
 		log.Fatal("no input file specified")
@@ -51,23 +53,41 @@ func main() {
 	if *lines != 0 {
 		opts = append(opts, bodkin.WithMaxCount(*lines))
 	}
-	arrowSchema, n, err := j2p.SchemaFromFile(*inputFile, opts...)
-	if err == bodkin.ErrInvalidInput {
+	u, err := j2p.UnifyFromFile(*inputFile, opts...)
+	if err != nil {
+		log.Fatal(err)
+	}
+	n := u.Count()
+	arrowSchema, err := u.Schema()
+	if err != nil {
 		fmt.Printf("schema creation error %v\n", err)
 	}
 	if arrowSchema == nil {
 		log.Fatal("nil schema")
 	}
 	log.Printf("schema from %d records\n", n)
-	fmt.Println(arrowSchema.String())
+	if *dryRun {
+		fmt.Print(u.SchemaReport())
+	} else {
+		fmt.Println(arrowSchema.String())
+	}
+	if *schemaOut != "" {
+		if err := j2p.WriteSidecarSchema(*schemaOut, arrowSchema, u.ChangeLog()...); err != nil {
+			log.Printf("schema sidecar error: %v", err)
+		}
+	}
 	if !*dryRun {
 		if *outputFile == "" {
 			log.Fatal("no output file specified")
 		}
 		log.Println("starting conversion to parquet")
 
-		n, err = j2p.RecordsFromFile(*inputFile, *outputFile, arrowSchema, nil)
+		var dead int
+		n, dead, err = j2p.RecordsFromFileWithOptions(*inputFile, *outputFile, arrowSchema, nil, j2p.RecordsOptions{DeadLetterPath: *deadLetterFile})
 		log.Printf("%d records written", n)
+		if dead > 0 {
+			log.Printf("%d records written to %s", dead, *deadLetterFile)
+		}
 		if err != nil {
 			log.Printf("parquet error: %v", err)
 		}
@@ -0,0 +1,55 @@
+package json2parquet
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+
+	json "github.com/goccy/go-json"
+)
+
+// deadLetter is a row that could not be converted, as written to a dead-letter file.
+type deadLetter struct {
+	Error string `json:"error"`
+	Datum string `json:"datum"`
+}
+
+// deadLetterWriter writes rows that could not be converted to a newline-delimited
+// JSON file.
+type deadLetterWriter struct {
+	f *os.File
+	w *bufio.Writer
+}
+
+func newDeadLetterWriter(path string) (*deadLetterWriter, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	return &deadLetterWriter{f: f, w: bufio.NewWriterSize(f, 1024*64)}, nil
+}
+
+// Write writes the raw datum and the error that prevented its conversion.
+func (d *deadLetterWriter) Write(datum []byte, cause error) error {
+	b, err := json.Marshal(deadLetter{Error: cause.Error(), Datum: string(bytes.TrimRight(datum, "\r\n"))})
+	if err != nil {
+		return err
+	}
+	if _, err := d.w.Write(b); err != nil {
+		return err
+	}
+	return d.w.WriteByte('\n')
+}
+
+// Close flushes and closes the dead-letter file, it is safe to call more than once.
+func (d *deadLetterWriter) Close() error {
+	if d.f == nil {
+		return nil
+	}
+	err := d.w.Flush()
+	if cerr := d.f.Close(); err == nil {
+		err = cerr
+	}
+	d.f = nil
+	return err
+}
@@ -2,10 +2,13 @@ package json2parquet
 
 import (
 	"bufio"
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"os"
+	"slices"
 
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
@@ -15,20 +18,37 @@ import (
 )
 
 func FromReader(r io.Reader, opts ...bodkin.Option) (*arrow.Schema, int, error) {
-	var err error
-	s := bufio.NewScanner(r)
+	u := unifyReader(r, opts...)
+	schema, err := u.Schema()
+	if err != nil {
+		return nil, u.Count(), err
+	}
+	return schema, u.Count(), err
+}
+
+// UnifyFromFile returns a Bodkin unified with the newline-delimited JSON in inputFile.
+func UnifyFromFile(inputFile string, opts ...bodkin.Option) (*bodkin.Bodkin, error) {
+	f, err := os.Open(inputFile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := bufio.NewReaderSize(f, 1024*32)
+	return unifyReader(r, opts...), nil
+}
+
+func unifyReader(r io.Reader, opts ...bodkin.Option) *bodkin.Bodkin {
 	u := bodkin.NewBodkin(opts...)
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, min(u.ScanBufferSize(), 1024*64)), u.ScanBufferSize())
 	for s.Scan() {
 		u.Unify(s.Bytes())
 		if u.Count() > u.MaxCount() {
 			break
 		}
 	}
-	schema, err := u.Schema()
-	if err != nil {
-		return nil, u.Count(), err
-	}
-	return schema, u.Count(), err
+	return u
 }
 
 func SchemaFromFile(inputFile string, opts ...bodkin.Option) (*arrow.Schema, int, error) {
@@ -42,11 +62,41 @@ func SchemaFromFile(inputFile string, opts ...bodkin.Option) (*arrow.Schema, int
 	return FromReader(r, opts...)
 }
 
+// RecordsFromFile converts newline-delimited JSON from inputFile to Parquet written to
+// outputFile, using the provided Arrow schema. opts are applied over the default writer
+// properties pq.DefaultWrtp.
+//
+// Returns the number of rows written. The conversion stops at the first error, see
+// RecordsFromFileWithOptions to dead-letter failing rows instead.
 func RecordsFromFile(inputFile, outputFile string, schema *arrow.Schema, munger func(io.Reader, io.Writer) error, opts ...parquet.WriterProperty) (int, error) {
-	n := 0
+	n, _, err := RecordsFromFileWithOptions(inputFile, outputFile, schema, munger, RecordsOptions{WriterProperties: opts})
+	return n, err
+}
+
+// RecordsOptions configures RecordsFromFileWithOptions.
+type RecordsOptions struct {
+	// DeadLetterPath, if not empty, is where rows that fail to be decoded or written
+	// are written as newline-delimited JSON along with the error, instead of failing
+	// the conversion.
+	DeadLetterPath string
+	// RootName, if not empty, is the root name of the Parquet schema instead of
+	// the default "bodkin".
+	RootName string
+	// Metadata pairs are added to the Parquet file's key-value metadata in key order.
+	Metadata map[string]string
+	// WriterProperties are applied over the default writer properties pq.DefaultWrtp.
+	WriterProperties []parquet.WriterProperty
+}
+
+// RecordsFromFileWithOptions converts newline-delimited JSON from inputFile to Parquet
+// written to outputFile, using the provided Arrow schema and o.
+//
+// Returns the number of rows written and the number of dead-lettered rows.
+func RecordsFromFileWithOptions(inputFile, outputFile string, schema *arrow.Schema, munger func(io.Reader, io.Writer) error, o RecordsOptions) (int, int, error) {
+	n, dead := 0, 0
 	f, err := os.Open(inputFile)
 	if err != nil {
-		return 0, err
+		return 0, 0, err
 	}
 	defer func() {
 		if r := recover(); r != nil {
@@ -55,19 +105,35 @@ func RecordsFromFile(inputFile, outputFile string, schema *arrow.Schema, munger
 		}
 	}()
 	defer f.Close()
+	wopts := o.WriterProperties
+	if o.RootName != "" {
+		wopts = append(slices.Clone(wopts), parquet.WithRootName(o.RootName))
+	}
 	var prp *parquet.WriterProperties = pq.DefaultWrtp
-	if len(opts) != 0 {
-		prp = parquet.NewWriterProperties(opts...)
+	if len(wopts) != 0 {
+		prp = pq.NewWriterProperties(wopts...)
 	}
 	pw, _, err := pq.NewParquetWriter(schema, prp, outputFile)
 	if err != nil {
-		return 0, err
+		return 0, 0, err
 	}
 	defer pw.Close()
+	for _, k := range slices.Sorted(maps.Keys(o.Metadata)) {
+		if err := pw.AppendKeyValueMetadata(k, o.Metadata[k]); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	var dl *deadLetterWriter
+	if o.DeadLetterPath != "" {
+		dl, err = newDeadLetterWriter(o.DeadLetterPath)
+		if err != nil {
+			return 0, 0, err
+		}
+		defer dl.Close()
+	}
 
 	var r io.Reader
-	var rdr *array.JSONReader
-	chunk := 1024
 	munger = nil
 	r = bufio.NewReaderSize(f, 1024*1024*128)
 	if munger != nil {
@@ -78,27 +144,107 @@ func RecordsFromFile(inputFile, outputFile string, schema *arrow.Schema, munger
 			defer pwr.Close()
 			munger(r, pwr)
 		}()
-		rdr = array.NewJSONReader(pr, schema, array.WithChunk(chunk))
-	} else {
-		rdr = array.NewJSONReader(r, schema, array.WithChunk(chunk))
+		r = pr
+	}
+	n, dead, err = writeLines(pw, schema, r, dl)
+	if err != nil {
+		return n, dead, err
+	}
+	if dl != nil {
+		if err := dl.Close(); err != nil {
+			return n, dead, err
+		}
 	}
+	err = pw.Close()
+	if err != nil {
+		return n, dead, err
+	}
+	return n, dead, err
+}
 
-	defer rdr.Release()
+// writeLines writes the newline-delimited JSON read from r to pw in chunks.
+// Returns the number of rows written and dead-lettered.
+func writeLines(pw *pq.ParquetWriter, schema *arrow.Schema, r io.Reader, dl *deadLetterWriter) (int, int, error) {
+	n, dead := 0, 0
+	chunk := 1024
+	br := bufio.NewReaderSize(r, 1024*1024)
+
+	lines := make([][]byte, 0, chunk)
+	for {
+		line, rerr := br.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			if line[len(line)-1] != '\n' {
+				line = append(line, '\n')
+			}
+			lines = append(lines, line)
+		}
+		if len(lines) >= chunk || (rerr != nil && len(lines) > 0) {
+			written, deadLettered, err := writeChunk(pw, schema, lines, dl)
+			n += written
+			dead += deadLettered
+			if err != nil {
+				return n, dead, err
+			}
+			lines = lines[:0]
+		}
+		if rerr != nil {
+			if errors.Is(rerr, io.EOF) {
+				break
+			}
+			return n, dead, rerr
+		}
+	}
+	return n, dead, nil
+}
 
-	for rdr.Next() {
-		rec := rdr.Record()
-		err1 := pw.WriteRecord(rec)
+// writeChunk builds a record from lines and writes it to pw. If that fails and dl
+// is not nil, lines are retried one by one and those failing are dead-lettered.
+// Returns the number of rows written and dead-lettered.
+func writeChunk(pw *pq.ParquetWriter, schema *arrow.Schema, lines [][]byte, dl *deadLetterWriter) (int, int, error) {
+	rec, err := buildRecord(schema, bytes.Join(lines, nil))
+	if err == nil {
+		err = pw.WriteRecord(rec)
+		written := int(rec.NumRows())
+		rec.Release()
+		if err == nil {
+			return written, 0, nil
+		}
+		err = fmt.Errorf("failed to write parquet record: %w", err)
+	}
+	if dl == nil {
+		return 0, 0, err
+	}
+	written, dead := 0, 0
+	for _, line := range lines {
+		rec, err := buildRecord(schema, line)
+		if err == nil {
+			err = pw.WriteRecord(rec)
+			rec.Release()
+		}
 		if err != nil {
-			err = errors.Join(err, fmt.Errorf("failed to write parquet record: %v", err1))
+			if err := dl.Write(line, err); err != nil {
+				return written, dead, err
+			}
+			dead++
+			continue
 		}
-		n = n + chunk
+		written++
 	}
+	return written, dead, nil
+}
+
+// buildRecord decodes newline-delimited JSON data to a single record.
+func buildRecord(schema *arrow.Schema, data []byte) (arrow.Record, error) {
+	rdr := array.NewJSONReader(bytes.NewReader(data), schema, array.WithChunk(-1))
+	defer rdr.Release()
+	ok := rdr.Next()
 	if err := rdr.Err(); err != nil {
-		return n, err
+		return nil, err
 	}
-	err = pw.Close()
-	if err != nil {
-		return n, err
+	if !ok {
+		return nil, fmt.Errorf("no rows decoded")
 	}
-	return n, err
+	rec := rdr.Record()
+	rec.Retain()
+	return rec, nil
 }
@@ -0,0 +1,288 @@
+package json2parquet
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/loicalleyne/bodkin/pq"
+	"github.com/loicalleyne/bodkin/reader"
+)
+
+// MaxOpenPartitionWriters is the number of partition files RecordsFromFilePartitioned
+// keeps open at once. Once reached, the least recently written partition file is
+// closed, and further rows of that partition are written to a new file.
+var MaxOpenPartitionWriters = 64
+
+// hiveDefaultPartition is the Hive partition value of null and empty values.
+const hiveDefaultPartition = "__HIVE_DEFAULT_PARTITION__"
+
+const partitionChunk = 1024
+
+// RecordsFromFilePartitioned converts newline-delimited JSON from inputFile to Parquet
+// files partitioned Hive-style by the values of the top-level scalar partitionCols of
+// schema, ie. outputDir/date=2024-01-01/part-00000.parquet. As in Hive, partition
+// columns are not written to the files, null or empty values go to the
+// __HIVE_DEFAULT_PARTITION__ partition and special characters of values are %-escaped.
+//
+// At most MaxOpenPartitionWriters files are open at once, so that high cardinality
+// partitioning doesn't exhaust file descriptors; a partition whose file was closed
+// continues in a new part file. opts are applied over the default writer properties
+// pq.DefaultWrtp.
+//
+// Returns the number of rows written.
+func RecordsFromFilePartitioned(inputFile, outputDir string, partitionCols []string, schema *arrow.Schema, opts ...parquet.WriterProperty) (int, error) {
+	dataSchema, err := partitionedSchema(schema, partitionCols)
+	if err != nil {
+		return 0, err
+	}
+	f, err := os.Open(inputFile)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	var prp *parquet.WriterProperties = pq.DefaultWrtp
+	if len(opts) != 0 {
+		prp = pq.NewWriterProperties(opts...)
+	}
+	p := &partitioner{
+		outputDir: outputDir,
+		schema:    dataSchema,
+		prp:       prp,
+		writers:   make(map[string]*partitionWriter),
+	}
+	defer p.close()
+
+	br := bufio.NewReaderSize(f, 1024*1024)
+	for {
+		line, rerr := br.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			if line[len(line)-1] != '\n' {
+				line = append(line, '\n')
+			}
+			dir, err := partitionDir(line, partitionCols)
+			if err != nil {
+				return p.n, err
+			}
+			if err := p.add(dir, line); err != nil {
+				return p.n, err
+			}
+		}
+		if rerr != nil {
+			if errors.Is(rerr, io.EOF) {
+				break
+			}
+			return p.n, rerr
+		}
+	}
+	return p.n, p.finish()
+}
+
+// partitionedSchema returns schema without partitionCols, returning an error if they
+// are not top-level scalar fields of schema.
+func partitionedSchema(schema *arrow.Schema, partitionCols []string) (*arrow.Schema, error) {
+	if len(partitionCols) == 0 {
+		return nil, fmt.Errorf("no partition columns")
+	}
+	for _, col := range partitionCols {
+		fields, ok := schema.FieldsByName(col)
+		if !ok {
+			return nil, fmt.Errorf("partition column %s not found in schema", col)
+		}
+		if arrow.IsNested(fields[0].Type.ID()) {
+			return nil, fmt.Errorf("partition column %s is not a scalar : %v", col, fields[0].Type)
+		}
+	}
+	var fields []arrow.Field
+	for _, f := range schema.Fields() {
+		if !slices.Contains(partitionCols, f.Name) {
+			fields = append(fields, f)
+		}
+	}
+	md := schema.Metadata()
+	return arrow.NewSchema(fields, &md), nil
+}
+
+// partitionDir returns the relative Hive-style directory of the partition of line.
+func partitionDir(line []byte, partitionCols []string) (string, error) {
+	m, err := reader.InputMap(line)
+	if err != nil {
+		return "", err
+	}
+	parts := make([]string, len(partitionCols))
+	for i, col := range partitionCols {
+		var value string
+		switch v := m[col].(type) {
+		case nil:
+		case string:
+			value = v
+		case map[string]any, []any:
+			return "", fmt.Errorf("partition column %s is not a scalar : %s", col, bytes.TrimSpace(line))
+		default:
+			value = fmt.Sprint(v)
+		}
+		if value == "" {
+			value = hiveDefaultPartition
+		} else {
+			value = escapePartitionValue(value)
+		}
+		parts[i] = escapePartitionValue(col) + "=" + value
+	}
+	return filepath.Join(parts...), nil
+}
+
+// escapePartitionValue %-escapes the characters Hive escapes in partition paths.
+func escapePartitionValue(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c < 0x20 || c == 0x7F || strings.IndexByte("\"#%'*/:=?\\{[]^", c) >= 0 {
+			fmt.Fprintf(&b, "%%%02X", c)
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// partitionWriter is the part file being written for a partition, and its rows waiting
+// to be written.
+type partitionWriter struct {
+	dir   string
+	pw    *pq.ParquetWriter
+	lines [][]byte
+	// number of part files created
+	parts int
+	// last use, for closing the least recently used file
+	used int
+}
+
+// partitioner routes rows to the part files of their partition.
+type partitioner struct {
+	outputDir string
+	schema    *arrow.Schema
+	prp       *parquet.WriterProperties
+	writers   map[string]*partitionWriter
+	open      int
+	pending   int
+	tick      int
+	n         int
+}
+
+// add queues line to the partition in dir, writing the partition's rows once a chunk
+// is queued. If too many rows are queued across partitions, those of the partition
+// with the most are written.
+func (p *partitioner) add(dir string, line []byte) error {
+	w, ok := p.writers[dir]
+	if !ok {
+		w = &partitionWriter{dir: dir}
+		p.writers[dir] = w
+	}
+	p.tick++
+	w.used = p.tick
+	w.lines = append(w.lines, line)
+	p.pending++
+	if len(w.lines) >= partitionChunk {
+		return p.flush(w)
+	}
+	if p.pending >= partitionChunk*MaxOpenPartitionWriters {
+		largest := w
+		for _, o := range p.writers {
+			if len(o.lines) > len(largest.lines) {
+				largest = o
+			}
+		}
+		return p.flush(largest)
+	}
+	return nil
+}
+
+// flush writes the queued rows of w to its part file, opening one if needed.
+func (p *partitioner) flush(w *partitionWriter) error {
+	if len(w.lines) == 0 {
+		return nil
+	}
+	if w.pw == nil {
+		if err := p.openWriter(w); err != nil {
+			return err
+		}
+	}
+	written, _, err := writeChunk(w.pw, p.schema, w.lines, nil)
+	p.n += written
+	p.pending -= len(w.lines)
+	w.lines = w.lines[:0]
+	return err
+}
+
+// openWriter creates a new part file for w, closing the least recently used part file
+// if MaxOpenPartitionWriters are open.
+func (p *partitioner) openWriter(w *partitionWriter) error {
+	if p.open >= max(MaxOpenPartitionWriters, 1) {
+		var lru *partitionWriter
+		for _, o := range p.writers {
+			if o.pw != nil && (lru == nil || o.used < lru.used) {
+				lru = o
+			}
+		}
+		if err := p.closeWriter(lru); err != nil {
+			return err
+		}
+	}
+	dir := filepath.Join(p.outputDir, w.dir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	pw, _, err := pq.NewParquetWriter(p.schema, p.prp, filepath.Join(dir, fmt.Sprintf("part-%05d.parquet", w.parts)))
+	if err != nil {
+		return err
+	}
+	w.pw = pw
+	w.parts++
+	p.open++
+	return nil
+}
+
+// closeWriter writes the queued rows of w and closes its part file.
+func (p *partitioner) closeWriter(w *partitionWriter) error {
+	err := p.flush(w)
+	pw := w.pw
+	w.pw = nil
+	p.open--
+	return errors.Join(err, pw.Close())
+}
+
+// finish writes the queued rows of every partition and closes the part files.
+func (p *partitioner) finish() error {
+	for _, dir := range slices.Sorted(maps.Keys(p.writers)) {
+		if err := p.flush(p.writers[dir]); err != nil {
+			return err
+		}
+	}
+	for _, dir := range slices.Sorted(maps.Keys(p.writers)) {
+		if w := p.writers[dir]; w.pw != nil {
+			if err := p.closeWriter(w); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// close closes the part files left open by an error.
+func (p *partitioner) close() {
+	for _, w := range p.writers {
+		if w.pw != nil {
+			w.pw.Close()
+			w.pw = nil
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package json2parquet
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+func TestRecordsFromFilePartitioned(t *testing.T) {
+	dir := t.TempDir()
+	sc := arrow.NewSchema([]arrow.Field{
+		{Name: "date", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "n", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+	}, nil)
+	input := writeFile(t, dir, "input.json", `{"date":"2024-01-01","n":1}
+{"date":"2024-01-02","n":2}
+{"date":null,"n":3}
+{"date":"a/b","n":4}
+{"date":"2024-01-01","n":5}
+`)
+	out := filepath.Join(dir, "out")
+	n, err := RecordsFromFilePartitioned(input, out, []string{"date"}, sc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 5 {
+		t.Errorf("RecordsFromFilePartitioned() = %d, want 5", n)
+	}
+	want := map[string][]string{
+		"date=2024-01-01/part-00000.parquet":                 {"1", "5"},
+		"date=2024-01-02/part-00000.parquet":                 {"2"},
+		"date=__HIVE_DEFAULT_PARTITION__/part-00000.parquet": {"3"},
+		"date=a%2Fb/part-00000.parquet":                      {"4"},
+	}
+	files, _ := filepath.Glob(filepath.Join(out, "*", "*.parquet"))
+	if len(files) != len(want) {
+		t.Errorf("files = %v, want %d", files, len(want))
+	}
+	for file, wantRows := range want {
+		rows, names := readRows(t, filepath.Join(out, file))
+		if !slices.Equal(names, []string{"n"}) {
+			t.Errorf("%s columns = %v, want the partition column removed", file, names)
+		}
+		if !slices.Equal(rows, wantRows) {
+			t.Errorf("%s rows = %v, want %v", file, rows, wantRows)
+		}
+	}
+}
+
+func TestRecordsFromFilePartitionedMaxOpen(t *testing.T) {
+	defer func(n int) { MaxOpenPartitionWriters = n }(MaxOpenPartitionWriters)
+	MaxOpenPartitionWriters = 1
+	dir := t.TempDir()
+	sc := arrow.NewSchema([]arrow.Field{
+		{Name: "p", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+		{Name: "n", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+	}, nil)
+	// more than a chunk per partition, so that each one is written while the other
+	// holds the only open file, and continues in a second part file
+	var input strings.Builder
+	rows := 2*partitionChunk + 200
+	for i := range rows {
+		fmt.Fprintf(&input, "{\"p\":%d,\"n\":%d}\n", i%2, i)
+	}
+	out := filepath.Join(dir, "out")
+	n, err := RecordsFromFilePartitioned(writeFile(t, dir, "input.json", input.String()), out, []string{"p"}, sc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != rows {
+		t.Errorf("RecordsFromFilePartitioned() = %d, want %d", n, rows)
+	}
+	files, _ := filepath.Glob(filepath.Join(out, "*", "*.parquet"))
+	total, continued := 0, false
+	for _, f := range files {
+		part := filepath.Base(filepath.Dir(f))
+		if filepath.Base(f) != "part-00000.parquet" {
+			continued = true
+		}
+		rows, _ := readRows(t, f)
+		for _, r := range rows {
+			v, _ := strconv.Atoi(r)
+			if want := "p=" + strconv.Itoa(v%2); part != want {
+				t.Errorf("row n=%s written to %s, want %s", r, part, want)
+			}
+		}
+		total += len(rows)
+	}
+	if !continued {
+		t.Errorf("files = %v, want a partition continued in a second part file", files)
+	}
+	if total != rows {
+		t.Errorf("files hold %d rows, want %d", total, rows)
+	}
+}
@@ -0,0 +1,63 @@
+package json2parquet
+
+import (
+	"os"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	json "github.com/goccy/go-json"
+	"github.com/loicalleyne/bodkin"
+)
+
+// sidecarSchema is the content of a sidecar schema file.
+type sidecarSchema struct {
+	Columns []sidecarColumn `json:"columns"`
+	Changes []bodkin.Change `json:"changes,omitempty"`
+}
+
+// sidecarColumn describes a column, or a field of a nested column.
+type sidecarColumn struct {
+	Name     string            `json:"name"`
+	Type     string            `json:"type"`
+	Nullable bool              `json:"nullable"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// Fields of a struct, or of the elements of a list of structs.
+	Fields []sidecarColumn `json:"fields,omitempty"`
+}
+
+// WriteSidecarSchema writes a JSON description of the columns of schema to path, ie.
+// output.schema.json next to output.parquet, for downstream consumers and catalogs.
+// changes, as returned by Bodkin.ChangeLog(), are included to show how the schema
+// evolved while it was inferred.
+func WriteSidecarSchema(path string, schema *arrow.Schema, changes ...bodkin.Change) error {
+	b, err := json.MarshalIndent(sidecarSchema{Columns: sidecarColumns(schema.Fields()), Changes: changes}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(b, '\n'), 0o644)
+}
+
+func sidecarColumns(fields []arrow.Field) []sidecarColumn {
+	columns := make([]sidecarColumn, 0, len(fields))
+	for _, f := range fields {
+		c := sidecarColumn{Name: f.Name, Type: f.Type.String(), Nullable: f.Nullable}
+		if f.Metadata.Len() > 0 {
+			c.Metadata = make(map[string]string, f.Metadata.Len())
+			for i, k := range f.Metadata.Keys() {
+				c.Metadata[k] = f.Metadata.Values()[i]
+			}
+		}
+		dt := f.Type
+		if l, ok := dt.(arrow.ListLikeType); ok {
+			dt = l.Elem()
+		}
+		if st, ok := dt.(*arrow.StructType); ok {
+			c.Type = f.Type.Name()
+			if dt != f.Type {
+				c.Type += "<struct>"
+			}
+			c.Fields = sidecarColumns(st.Fields())
+		}
+		columns = append(columns, c)
+	}
+	return columns
+}
@@ -0,0 +1,113 @@
+package bodkin
+
+import (
+	"fmt"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+// DefaultLintMaxDepth is the nesting depth beyond which Lint flags a field.
+const DefaultLintMaxDepth = 8
+
+// LintFinding is a risky field found by a LintRule.
+type LintFinding struct {
+	// Name of the rule which found it.
+	Rule    string
+	Dotpath string
+	Message string
+}
+
+func (l LintFinding) String() string {
+	return fmt.Sprintf("%s: %s", l.Rule, l.Message)
+}
+
+// LintField is a field of the unified schema, or a field which could not be evaluated
+// yet, as checked by a LintRule.
+type LintField struct {
+	Dotpath string
+	// Arrow type of the field, nil if it could not be evaluated yet.
+	Type arrow.DataType
+	// Number of levels of nesting of the field, 1 for top-level fields. The elements
+	// of a list of nested values are a level below the list.
+	Depth int
+	// Number of inputs in which the field was present with a null value.
+	Nulls int
+	// Number of inputs unified.
+	Inputs int
+	// Statistics of the field's values, nil unless WithCollectStats is set.
+	Stat *FieldStat
+}
+
+// LintRule returns the findings of a rule for field f, nil if there are none.
+type LintRule func(f LintField) []LintFinding
+
+// LintAlwaysNull flags fields which were null in every input in which they were seen.
+func LintAlwaysNull(f LintField) []LintFinding {
+	alwaysNull := f.Type == nil && f.Nulls > 0 && f.Nulls >= f.Inputs
+	if f.Stat != nil {
+		alwaysNull = f.Stat.Nulls > 0 && f.Stat.NonNulls == 0
+	}
+	if !alwaysNull {
+		return nil
+	}
+	return []LintFinding{{Rule: "always-null", Dotpath: f.Dotpath, Message: fmt.Sprintf("column %s is always null", f.Dotpath)}}
+}
+
+// LintSingleValue flags scalar fields for which a single distinct value was seen in
+// more than one input. It requires WithCollectStats.
+func LintSingleValue(f LintField) []LintFinding {
+	if f.Stat == nil || f.Stat.NonNulls < 2 || f.Stat.Distinct != 1 {
+		return nil
+	}
+	return []LintFinding{{Rule: "single-value", Dotpath: f.Dotpath, Message: fmt.Sprintf("column %s has a single observed value", f.Dotpath)}}
+}
+
+// LintDeepNesting returns a LintRule flagging fields nested more than maxDepth levels
+// deep. Only the first level beyond maxDepth of each branch is flagged.
+func LintDeepNesting(maxDepth int) LintRule {
+	return func(f LintField) []LintFinding {
+		if f.Depth != maxDepth+1 {
+			return nil
+		}
+		return []LintFinding{{Rule: "deep-nesting", Dotpath: f.Dotpath, Message: fmt.Sprintf("%s nests %d levels deep", f.Dotpath, f.Depth)}}
+	}
+}
+
+// Lint checks the fields seen to date, evaluated or not, against the rules
+// LintAlwaysNull, LintSingleValue and LintDeepNesting(DefaultLintMaxDepth), followed by
+// the rules added with WithLintRule, and returns their findings in field order. Rules
+// relying on statistics only report findings if WithCollectStats is set.
+func (u *Bodkin) Lint() []LintFinding {
+	rules := append([]LintRule{LintAlwaysNull, LintSingleValue, LintDeepNesting(DefaultLintMaxDepth)}, u.lintRules...)
+	var findings []LintFinding
+	for _, f := range u.lintFields() {
+		for _, rule := range rules {
+			findings = append(findings, rule(f)...)
+		}
+	}
+	return findings
+}
+
+// lintFields returns the known fields followed by the untyped fields, in the order in
+// which they were seen.
+func (u *Bodkin) lintFields() []LintField {
+	var fields []LintField
+	add := func(f *fieldPos, typed bool) {
+		p := f.dotPath()
+		lf := LintField{Dotpath: p, Depth: len(f.path), Nulls: u.nullCounts[p], Inputs: u.unified}
+		if typed {
+			lf.Type = f.field.Type
+		}
+		if s, ok := u.stats[p]; ok {
+			lf.Stat = &FieldStat{Nulls: s.nulls, NonNulls: s.nonNulls, Distinct: s.distinct.count()}
+		}
+		fields = append(fields, lf)
+	}
+	for pair := u.knownFields.Oldest(); pair != nil; pair = pair.Next() {
+		add(pair.Value, true)
+	}
+	for pair := u.untypedFields.Oldest(); pair != nil; pair = pair.Next() {
+		add(pair.Value, false)
+	}
+	return fields
+}
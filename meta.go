@@ -0,0 +1,92 @@
+package bodkin
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+// observeMeta records the field metadata described by the value of the top-level key
+// set with WithMetaConvention, an object of dotpaths, relative to the top level, to
+// objects of metadata key-value pairs, ie. {"price":{"unit":"USD"}}. Values which are
+// not strings are stored as JSON. Later inputs override the values of earlier ones.
+func (u *Bodkin) observeMeta(v any) {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return
+	}
+	for path, kv := range m {
+		pairs, ok := kv.(map[string]any)
+		if !ok {
+			continue
+		}
+		dotpath := cleanDotpath(path)
+		md, ok := u.fieldMeta[dotpath]
+		if !ok {
+			md = make(map[string]string)
+			u.fieldMeta[dotpath] = md
+		}
+		for k, val := range pairs {
+			switch t := val.(type) {
+			case string:
+				md[k] = t
+			case nil:
+				delete(md, k)
+			default:
+				b, err := json.Marshal(t)
+				if err != nil {
+					md[k] = fmt.Sprint(t)
+					continue
+				}
+				md[k] = string(b)
+			}
+		}
+	}
+}
+
+// tagMeta returns field with the metadata described for the path of f added to its
+// metadata, sorted by key, replacing existing values of the same keys.
+func (f *fieldPos) tagMeta(field arrow.Field) arrow.Field {
+	md, ok := f.owner.fieldMeta[f.dotPath()]
+	if !ok || len(md) == 0 {
+		return field
+	}
+	keys := make([]string, 0, len(md))
+	for k := range md {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	var ks, vs []string
+	for i, k := range field.Metadata.Keys() {
+		if _, ok := md[k]; !ok {
+			ks = append(ks, k)
+			vs = append(vs, field.Metadata.Values()[i])
+		}
+	}
+	for _, k := range keys {
+		ks = append(ks, k)
+		vs = append(vs, md[k])
+	}
+	field.Metadata = arrow.NewMetadata(ks, vs)
+	return field
+}
+
+// UnmatchedMeta returns the sorted dotpaths described in the metadata blocks read with
+// WithMetaConvention which are not fields of the unified schema. Their metadata is kept
+// and attached if a later input adds the field.
+func (u *Bodkin) UnmatchedMeta() []string {
+	var paths []string
+	for dotpath := range u.fieldMeta {
+		if _, ok := u.knownFields.Get(dotpath); ok {
+			continue
+		}
+		if _, ok := u.untypedFields.Get(dotpath); ok {
+			continue
+		}
+		paths = append(paths, dotpath)
+	}
+	slices.Sort(paths)
+	return paths
+}
@@ -0,0 +1,46 @@
+package bodkin
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ScanOffset returns the byte offset in the io.Reader set with WithIOReader up to which
+// UnifyScan has consumed datum, including the delimiter of the last one. It can be
+// saved as a checkpoint and passed to WithStartOffset to resume a crashed scan.
+func (u *Bodkin) ScanOffset() int64 {
+	if u.stream != nil {
+		return u.scanStart + u.stream.InputOffset()
+	}
+	return u.scanOffset
+}
+
+// seekStart seeks the io.Reader to the offset set with WithStartOffset, the first time
+// UnifyScan is called, then discards the rest of the datum the offset falls in unless
+// it follows a delimiter, so that scanning starts at a datum boundary.
+func (u *Bodkin) seekStart() error {
+	if u.startOffset <= 0 || u.started {
+		return nil
+	}
+	u.started = true
+	rs, ok := u.rr.(io.ReadSeeker)
+	if !ok {
+		return fmt.Errorf("%w : start offset requires an io.ReadSeeker", ErrInvalidInput)
+	}
+	if u.jsonStream {
+		return fmt.Errorf("%w : start offset is not supported with a JSON stream", ErrInvalidInput)
+	}
+	// the byte before the offset is read to check whether it ends a datum
+	if _, err := rs.Seek(u.startOffset-1, io.SeekStart); err != nil {
+		return err
+	}
+	u.br.Reset(u.rr)
+	partial, err := u.br.ReadBytes(u.delim)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	u.scanOffset = u.startOffset - 1 + int64(len(partial))
+	u.scanStart = u.scanOffset
+	return nil
+}
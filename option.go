@@ -3,6 +3,9 @@ package bodkin
 import (
 	"bufio"
 	"io"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/loicalleyne/bodkin/reader"
 )
 
 // WithInferTimeUnits() enables scanning input string values for time, date and timestamp types.
@@ -45,15 +48,534 @@ func WithMaxCount(i int) Option {
 	}
 }
 
+// WithSampleEveryNth makes UnifyScan unify only every nth datum, starting with the
+// first, skipping the others without decoding them. Combined with WithMaxCount, the
+// sample is spread across the whole input rather than taken from its head.
+// ScannedCount() returns the number of datum read, including skipped ones.
+func WithSampleEveryNth(n int) Option {
+	return func(cfg config) {
+		cfg.sampleEvery = n
+	}
+}
+
 // WithIOReader provides an io.Reader for a Bodkin to use with UnifyScan(), along
 // with a delimiter to use to split datum in the data stream.
 // Default delimiter '\n' if delimiter is not provided.
 func WithIOReader(r io.Reader, delim byte) Option {
 	return func(cfg config) {
 		cfg.rr = r
-		cfg.br = bufio.NewReaderSize(cfg.rr, 1024*16)
+		size := 1024 * 16
+		if cfg.scanBufferSize > 0 {
+			size = cfg.scanBufferSize
+		}
+		cfg.br = bufio.NewReaderSize(cfg.rr, size)
 		if delim != '\n' {
 			cfg.delim = delim
 		}
 	}
 }
+
+// WithScanBufferSize sets the size in bytes of the buffer used to read datum from the
+// io.Reader set with WithIOReader, and the maximum datum size returned by ScanBufferSize
+// for callers scanning input with a bufio.Scanner, so that multi-megabyte single-line
+// datum can be read. It is also used by the Bodkin Reader.
+func WithScanBufferSize(n int) Option {
+	return func(cfg config) {
+		cfg.scanBufferSize = n
+		if cfg.rr != nil && n > 0 {
+			cfg.br = bufio.NewReaderSize(cfg.rr, n)
+		}
+	}
+}
+
+// WithStartOffset starts UnifyScan at byte offset off of the io.Reader set with
+// WithIOReader, which must be an io.ReadSeeker, ie. a ScanOffset saved by a previous
+// scan. If off falls inside a datum, the rest of that datum is skipped so that
+// scanning starts at the next one. It is not supported with WithJSONStream.
+func WithStartOffset(off int64) Option {
+	return func(cfg config) {
+		cfg.startOffset = off
+	}
+}
+
+// WithFieldRenames names the fields at the dotpaths keyed in renames, ie. "$user.fname",
+// with their mapped names, ie. "first_name", when they are evaluated, so that columns are
+// named canonically from the first record. As with WithNameSanitizer the original key is
+// preserved in the field's metadata under reader.OriginalNameKey, from which the Bodkin
+// Reader loads the renamed columns. Renamed fields are not sanitized.
+func WithFieldRenames(renames map[string]string) Option {
+	return func(cfg config) {
+		cfg.renames = make(map[string]string, len(renames))
+		for p, name := range renames {
+			cfg.renames[cleanDotpath(p)] = name
+		}
+	}
+}
+
+// WithNameSanitizer applies fn to each input field name to produce a valid
+// Parquet/SQL column name. If fn is nil, DefaultNameSanitizer is used.
+// Sibling fields whose sanitized names collide are disambiguated with a numeric
+// suffix (ie. user_name, user_name_2). The original name of any renamed field is
+// preserved in the field's metadata under reader.OriginalNameKey, which the
+// Bodkin Reader uses to locate the field's values in the input data.
+func WithNameSanitizer(fn func(string) string) Option {
+	return func(cfg config) {
+		if fn == nil {
+			fn = DefaultNameSanitizer
+		}
+		cfg.nameSanitizer = fn
+	}
+}
+
+// WithResolveEmptyAs materializes fields that are still unevaluated when Schema() is
+// called, instead of excluding them from the schema. Empty arrays become lists of
+// type t and null fields become type t. Empty objects become strings holding their
+// JSON text, which the reader can load whatever t is, along with the fields found
+// in them. Use arrow.NULL for Null-typed columns, empty objects included, otherwise
+// t should be a scalar type, ie. arrow.STRING; nested types fall back to arrow.STRING.
+// Resolved fields are recorded in Changes() and are merged with subsequent inputs
+// as though they had been evaluated from data.
+func WithResolveEmptyAs(t arrow.Type) Option {
+	return func(cfg config) {
+		cfg.resolveEmpty = true
+		cfg.resolveEmptyAs = t
+	}
+}
+
+// WithFirstRecordFieldOrder preserves the field order of the first unified input as the
+// top-level column order of the schema. Fields discovered in subsequent inputs are
+// appended in the order they are found.
+// Key order is only available for json string or []byte and Go struct inputs, a
+// map[string]any first input has no inherent order.
+func WithFirstRecordFieldOrder() Option {
+	return func(cfg config) {
+		cfg.firstRecordOrder = true
+	}
+}
+
+// WithSortedFields sorts the fields of the schema by name at every level of nesting,
+// so that the schema is the same whatever the order of keys in the inputs and the order
+// in which fields were discovered.
+//
+// Without it fields are ordered as they were first evaluated: keys of an input are in
+// map order, which is not deterministic, and fields discovered in later inputs are
+// appended. WithFirstRecordFieldOrder keeps the key order of the first input's top-level
+// fields instead, it has no effect when WithSortedFields is set.
+func WithSortedFields() Option {
+	return func(cfg config) {
+		cfg.sortedFields = true
+	}
+}
+
+// WithBoolIntAsInt64 merges a field seen as both a boolean and an integer, ie. true,
+// then 1, then 0, into an integer column instead of a string, and readers load true
+// and false into it as 1 and 0. A boolean field becomes an int64; an integer field
+// keeps its type. With WithBoolTokens, numbers listed as tokens are still inferred as
+// booleans, so a field of tokens and other integers merges the same way.
+func WithBoolIntAsInt64() Option {
+	return func(cfg config) {
+		cfg.boolIntAsInt64 = true
+	}
+}
+
+// WithPromoteSingletonToList merges a field seen as both a scalar and an array of that
+// scalar's type, ie. "x" then ["x","y"], into a list column instead of a conflict.
+// Readers load the scalar values of a list column as single-element lists.
+func WithPromoteSingletonToList() Option {
+	return func(cfg config) {
+		cfg.promoteSingletons = true
+	}
+}
+
+// WithJSONBlobFallback keeps a field whose structure varies between inputs, ie. an
+// object in one and an array or a scalar in another, as a string column holding the
+// JSON text of whatever is at its path, instead of coercing its values, so that the
+// data is preserved for JSON-aware queries. The field's metadata is marked with
+// reader.JSONBlobKey, from which readers serialize its values to JSON.
+func WithJSONBlobFallback() Option {
+	return func(cfg config) {
+		cfg.jsonBlobFallback = true
+	}
+}
+
+// WithAdditiveOnly restricts schema evolution to adding new fields: a field whose type
+// differs in a later input keeps its existing type, ignoring WithTypeConversion and
+// WithConflictResolver, and the skipped change is recorded as a conflict in Err()
+// wrapping ErrFieldTypeKept. Unlike Freeze, new fields are still added, so that the
+// schema stays append-compatible with files written with earlier versions of it.
+func WithAdditiveOnly() Option {
+	return func(cfg config) {
+		cfg.additiveOnly = true
+	}
+}
+
+// WithLintRule adds rule to the rules Lint checks the fields against, after the
+// built-in rules. Rules are checked in the order in which they were added.
+func WithLintRule(rule LintRule) Option {
+	return func(cfg config) {
+		cfg.lintRules = append(cfg.lintRules, rule)
+	}
+}
+
+// WithConflictResolver provides a function that is consulted when an input's field
+// type differs from the unified schema's, before the default type conversion rules.
+// It receives the field's dotpath and its current and new types.
+//
+// If fn returns a non-nil type, the field is changed to that type and the default
+// rules are skipped. If fn returns a nil type and nil error, the default rules apply.
+// If fn returns an error, the field keeps its current type and the conflict is
+// reported by Err().
+func WithConflictResolver(fn func(dotpath string, old, new arrow.DataType) (arrow.DataType, error)) Option {
+	return func(cfg config) {
+		cfg.conflictResolver = fn
+	}
+}
+
+// WithExactIntegerWidth tracks the range of values observed for each integer field,
+// and Schema() uses the narrowest Arrow integer type that fits all of them:
+// uint8, uint16 or uint32 if no negative value was seen, otherwise int8, int16
+// or int32, falling back to int64.
+// The unified schema itself keeps int64 fields so that later inputs with wider values
+// can be merged. The Bodkin Reader returns an error when loading a value that
+// overflows its column's type.
+// The element type of integer arrays is not narrowed.
+func WithExactIntegerWidth() Option {
+	return func(cfg config) {
+		cfg.exactIntegerWidth = true
+	}
+}
+
+// WithLargeTypes uses 64-bit offset types for all list and string fields, ie.
+// arrow.LARGE_LIST and arrow.LARGE_STRING instead of arrow.LIST and arrow.STRING,
+// for columns whose data may exceed 2GB in a single record.
+// This is a schema-wide setting, type conversions to strings and lists also use the
+// large variants.
+func WithLargeTypes() Option {
+	return func(cfg config) {
+		cfg.largeTypes = true
+	}
+}
+
+// WithNullOnlyAsType materializes fields that were present with a null value in
+// every input unified to date as nullable columns of type dt when Schema() is called,
+// instead of excluding them from the schema. Fields absent from any input are still
+// excluded. If dt is nil, arrow.BinaryTypes.String is used.
+// Resolved fields are recorded in Changes().
+func WithNullOnlyAsType(dt arrow.DataType) Option {
+	return func(cfg config) {
+		if dt == nil {
+			dt = arrow.BinaryTypes.String
+		}
+		cfg.nullOnlyAs = dt
+	}
+}
+
+// WithDistinguishAbsentFromNull tracks whether each field was absent from an input or
+// present with a null value, reported by FieldPresence(). Fields present only as
+// null, in the inputs that had them, are then treated as null-only by
+// WithNullOnlyAsType even if they were absent from other inputs.
+func WithDistinguishAbsentFromNull() Option {
+	return func(cfg config) {
+		cfg.distinguishAbsent = true
+	}
+}
+
+// WithNullColumnsAsNullType materializes fields that were present with a null value in
+// every input unified to date as arrow.Null columns when Schema() is called, preserving
+// the column for downstream systems which union it with later non-null data.
+// It is equivalent to WithNullOnlyAsType(arrow.Null).
+func WithNullColumnsAsNullType() Option {
+	return WithNullOnlyAsType(arrow.Null)
+}
+
+// WithMaxDepth stops descending into nested objects and arrays past n levels of nesting,
+// top-level fields being at depth 1. Nested content of a field at depth n is kept as
+// a JSON string column and the field is noted in Changes(). This guards against
+// deeply nested or adversarial input.
+func WithMaxDepth(n int) Option {
+	return func(cfg config) {
+		cfg.maxDepth = n
+	}
+}
+
+// WithBoolTokens infers fields whose values are one of the given string or number
+// tokens as arrow.BOOL, eg. "Y"/"N" or 0/1, and loads the tokens as true or false
+// when the Reader is created with Bodkin.NewReader. A field seen with both numeric
+// tokens and other integers is changed to arrow.INT64 when WithTypeConversion is set.
+// By default only JSON booleans and the strings "true" and "false" are inferred as
+// booleans.
+func WithBoolTokens(trueSet, falseSet []string) Option {
+	return func(cfg config) {
+		cfg.boolTrue = trueSet
+		cfg.boolFalse = falseSet
+	}
+}
+
+// WithNumericAsFloat64 infers all JSON numbers as arrow.FLOAT64, whether or not they
+// have a fractional part, so that a field first seen with an integer value doesn't
+// change type when a later input has a decimal value. Numbers in Go struct and map
+// inputs keep the type of their Go value.
+func WithNumericAsFloat64() Option {
+	return func(cfg config) {
+		cfg.numericAsFloat64 = true
+	}
+}
+
+// WithAllowNonFiniteFloats accepts the bare NaN, Infinity and Inf tokens emitted by
+// lenient JSON encoders in JSON input, and infers fields with these tokens, bare or
+// quoted, as arrow.FLOAT64. Readers created with Bodkin.NewReader load them as
+// non-finite float values; NaN values are excluded from Parquet column statistics
+// by the Parquet writer.
+func WithAllowNonFiniteFloats() Option {
+	return func(cfg config) {
+		cfg.allowNonFinite = true
+	}
+}
+
+// WithTimeLayouts specifies Go time layouts, eg. "01/02/2006" or "02 Jan 2006 15:04",
+// used to infer date and timestamp fields from strings not matched by the built-in
+// formats. Layouts without a time of day are inferred as arrow.DATE32, others as
+// arrow.TIMESTAMP. Layouts are tried in order after the built-in formats and the
+// first that parses a value determines its type, so more specific layouts should
+// come first. Readers created with Bodkin.NewReader parse values with the same layouts.
+func WithTimeLayouts(layouts ...string) Option {
+	return func(cfg config) {
+		cfg.timeLayouts = layouts
+	}
+}
+
+// WithCaptureEnums records the distinct values of string fields, up to maxValues per
+// field, and adds them sorted and comma separated to the Arrow field metadata key "enum",
+// ie. "A,B,C", so that a catalog can show the allowed values. Capture is abandoned for a
+// field, and no metadata is added, once more than maxValues distinct values are seen.
+func WithCaptureEnums(maxValues int) Option {
+	return func(cfg config) {
+		cfg.maxEnumValues = maxValues
+	}
+}
+
+// WithMetaConvention reads the top-level key of each input named key, ie. "_meta", as
+// a block of Arrow field metadata instead of a field. Its value is an object of field
+// dotpaths to objects of metadata key-value pairs, ie.
+// {"_meta":{"price":{"unit":"USD"},"item.sku":{"pii":"false"}}}, which are attached to
+// the metadata of those fields in Schema(). Values which are not strings are stored as
+// JSON and null values remove a key. Metadata described for a field which does not
+// exist is kept until an input adds the field, see UnmatchedMeta.
+func WithMetaConvention(key string) Option {
+	return func(cfg config) {
+		cfg.metaKey = key
+	}
+}
+
+// WithSemanticDetection tags string fields whose values are IPv4 addresses, IPv6
+// addresses or email addresses with the Arrow field metadata key "semantic" and value
+// "ipv4", "ipv6" or "email". The field type stays a string.
+// A field is tagged if at least minFraction of its sampled string values match, which
+// defaults to 0.95 if minFraction is not between 0 and 1.
+func WithSemanticDetection(minFraction float64) Option {
+	return func(cfg config) {
+		if minFraction <= 0 || minFraction > 1 {
+			minFraction = 0.95
+		}
+		cfg.semanticFraction = minFraction
+	}
+}
+
+// WithCollectStats collects the null count, non-null count and approximate distinct
+// count of each field's values during unification, returned by FieldStats().
+func WithCollectStats() Option {
+	return func(cfg config) {
+		cfg.collectStats = true
+	}
+}
+
+// WithDuplicateKeyPolicy specifies how a key appearing more than once in the same
+// JSON object is handled: reader.DuplicateKeyLastWins (the default) keeps the last
+// value, reader.DuplicateKeyFirstWins keeps the first and reader.DuplicateKeyError
+// rejects the input with an error wrapping reader.ErrDuplicateKey.
+// Readers created with Bodkin.NewReader use the same policy.
+func WithDuplicateKeyPolicy(policy reader.DuplicateKeyPolicy) Option {
+	return func(cfg config) {
+		cfg.dupKeys = policy
+	}
+}
+
+// WithInferFixedSizeList infers arrays which had the same length in every input unified
+// to date as fixed size lists, ie. arrow.FixedSizeListOf(768, arrow.PrimitiveTypes.Float64)
+// for embedding vectors. If an array at the same path later has a different length,
+// the field reverts to a list and the change is recorded in Changes().
+// Readers load arrays of another length into a fixed size list column as null.
+func WithInferFixedSizeList() Option {
+	return func(cfg config) {
+		cfg.inferFixedSizeList = true
+	}
+}
+
+// WithBigIntegersAsString infers integers beyond the range of int64 as strings. By
+// default they are inferred as arrow.Decimal128 of precision 38 and scale 0, or as
+// strings if they have more than 38 digits.
+func WithBigIntegersAsString() Option {
+	return func(cfg config) {
+		cfg.bigIntegersAsString = true
+	}
+}
+
+// WithErrorSink writes each datum UnifyScan could not decode to w, followed by a
+// newline, ie. to a dead-letter file, while valid datum continue to be unified.
+// ErrorCount() returns the number of such datum.
+func WithErrorSink(w io.Writer) Option {
+	return func(cfg config) {
+		cfg.errorSink = w
+	}
+}
+
+// WithDatumTransform applies fn to each raw JSON datum before it is decoded by Unify,
+// UnifyBatch and UnifyScan, and by readers created with Bodkin.NewReader, ie. a Bloblang
+// mapping stripping nulls and empty values, avoiding a separate cleaning pass over the
+// data. A datum for which fn returns an error, wrapping reader.ErrDatumTransform, is
+// handled as an undecodable datum: UnifyScan writes it to the error sink set with
+// WithErrorSink.
+func WithDatumTransform(fn func([]byte) ([]byte, error)) Option {
+	return func(cfg config) {
+		cfg.datumTransform = fn
+	}
+}
+
+// WithWrapTopLevelValues unifies raw JSON datum which are not objects, ie. the strings,
+// numbers or arrays of a simple event log, as objects with the value under the field
+// name, reader.DefaultValueField ("value") if name is empty, so that "a" is unified as
+// {"value":"a"}. Readers created with Bodkin.NewReader wrap datum the same way.
+func WithWrapTopLevelValues(name string) Option {
+	return func(cfg config) {
+		if name == "" {
+			name = reader.DefaultValueField
+		}
+		cfg.valueField = name
+	}
+}
+
+// WithJSONStream makes UnifyScan and UnifyAndRead read the io.Reader set with
+// WithIOReader as a stream of concatenated JSON values, ie. {...}{...}, optionally
+// separated by whitespace, instead of splitting it on the delimiter. Values may span
+// lines. A malformed value ends the scan with an error.
+// Readers created with Bodkin.NewReader read streams the same way.
+func WithJSONStream() Option {
+	return func(cfg config) {
+		cfg.jsonStream = true
+	}
+}
+
+// WithISODurations infers string values which are ISO 8601 durations, ie. "PT1H30M" or
+// "P3D", as arrow.Duration of unit, ie. arrow.Millisecond. Only durations of weeks,
+// days, hours, minutes and seconds are recognized, as years and months have no fixed
+// length. Readers load such strings into duration columns in the column's unit.
+func WithISODurations(unit arrow.TimeUnit) Option {
+	return func(cfg config) {
+		cfg.isoDurations = true
+		cfg.durationUnit = unit
+	}
+}
+
+// WithTreatBlankAsNull treats string values which are empty or only white space, as
+// defined by reader.IsBlank, as null when inferring types, so that a column of numbers
+// with occasional blank values is inferred as numeric. Readers created with
+// Bodkin.NewReader load blank values as null.
+func WithTreatBlankAsNull() Option {
+	return func(cfg config) {
+		cfg.blankAsNull = true
+	}
+}
+
+// WithCaseInsensitiveCollisionCheck records sibling fields whose column names only
+// differ by case, ie. Name and name, as conflicts in Err() wrapping
+// ErrFieldNameCollision, as they would clobber each other in case-insensitive targets.
+// Names are compared after sanitizing with WithNameSanitizer, across all inputs.
+// Both fields are kept in the schema.
+func WithCaseInsensitiveCollisionCheck() Option {
+	return func(cfg config) {
+		cfg.caseCollisionCheck = true
+	}
+}
+
+// WithCaseInsensitiveMerge merges fields whose names only differ by case, ie. UserID
+// and userid, into one column named as the first seen, instead of adding a column for
+// each. If an object has several such keys, the one spelled as the column's name is
+// kept, or else the smallest, and the others are recorded as conflicts in Err()
+// wrapping ErrFieldNameCollision. Readers created by the Bodkin load the keys the
+// same way, see reader.WithCaseInsensitiveKeys.
+func WithCaseInsensitiveMerge() Option {
+	return func(cfg config) {
+		cfg.caseInsensitiveMerge = true
+	}
+}
+
+// WithMaxFieldCount caps the width of the schema: once n fields, counting nested ones,
+// are known, new top-level fields are not added as columns but diverted into a
+// map<string, string> column named _extra, loaded by readers with the keys of each
+// datum which are not in the schema. Diverted keys are recorded in Changes() wrapping
+// ErrFieldDiverted. New fields nested under known fields are still added. An input
+// field named _extra is always diverted.
+func WithMaxFieldCount(n int) Option {
+	return func(cfg config) {
+		cfg.maxFieldCount = n
+	}
+}
+
+// WithDate64 infers the fields at dotpaths, ie. "$created_date", as arrow.Date64 when
+// their values are integer numbers, which are read as epoch milliseconds, or
+// YYYY-MM-DD dates. A number can't tell a date from a timestamp, so only the listed
+// fields are inferred as Date64; at those paths this takes precedence over other
+// inference, including WithRawNumberColumn and WithInferTimeUnits. Readers load values
+// truncated to the start of their day in UTC.
+func WithDate64(dotpaths ...string) Option {
+	return func(cfg config) {
+		if cfg.date64 == nil {
+			cfg.date64 = make(map[string]bool)
+		}
+		for _, p := range dotpaths {
+			cfg.date64[cleanDotpath(p)] = true
+		}
+	}
+}
+
+// WithRawNumberColumn infers the numeric field at dotpath, ie. "$amount", as a string
+// holding the number's exact input text, so that 1.10 is not loaded as 1.1. Readers
+// load JSON numbers into string columns as their input text. It can be used once for
+// each field, numbers elsewhere are parsed as usual.
+func WithRawNumberColumn(dotpath string) Option {
+	return func(cfg config) {
+		if cfg.rawNumbers == nil {
+			cfg.rawNumbers = make(map[string]bool)
+		}
+		cfg.rawNumbers[cleanDotpath(dotpath)] = true
+	}
+}
+
+// WithRunEndEncoded outputs the top-level scalar columns at dotpaths, ie. "$tag", as
+// run-end encoded arrays of their type with int32 run ends, for columns which are
+// constant over long runs of rows. Readers build such columns with a
+// RunEndEncodedBuilder, starting a new run when a value differs from the previous one.
+// pq.ParquetWriter decodes run-end encoded columns, which Parquet doesn't support.
+func WithRunEndEncoded(dotpaths ...string) Option {
+	return func(cfg config) {
+		if cfg.runEndEncoded == nil {
+			cfg.runEndEncoded = make(map[string]bool)
+		}
+		for _, p := range dotpaths {
+			cfg.runEndEncoded[cleanDotpath(p)] = true
+		}
+	}
+}
+
+// WithGeoJSONDetection recognizes objects which are GeoJSON geometries, ie.
+// {"type":"Point","coordinates":[1.0,2.0]}, and tags their struct field with the Arrow
+// field metadata key "geotype" and the lowercase geometry type, ie. "point", or
+// "geometry" if several types were seen at the same path. If every geometry at a path
+// had the same type and number of dimensions, coordinates are normalized to float64
+// positions of fixed size lists, ie. arrow.FixedSizeListOf(2, arrow.PrimitiveTypes.Float64)
+// for points, nested in lists for the other types.
+func WithGeoJSONDetection() Option {
+	return func(cfg config) {
+		cfg.geoJSON = true
+	}
+}
@@ -0,0 +1,68 @@
+package pq
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/compress"
+)
+
+// EstimateParquetSize estimates the number of compressed bytes per row a Parquet
+// file written with DefaultWrtp would use, by writing sampleRecords to an in-memory
+// buffer. The fixed overhead of the file's footer and metadata is excluded. The
+// estimate is fractional, narrow rows often compress to less than a byte.
+//
+// Multiply the estimate by the expected row count to extrapolate the size of a file,
+// ie. to choose row group or file rollover sizes. The estimate is only as
+// representative as the sample; dictionary encoding in particular compresses
+// better as the number of rows grows.
+func EstimateParquetSize(sc *arrow.Schema, sampleRecords []arrow.Record) (float64, error) {
+	return estimateParquetSize(sc, sampleRecords, DefaultWrtp)
+}
+
+// EstimateParquetSizeCompressed estimates the number of compressed bytes per row as
+// EstimateParquetSize does, for a file written with DefaultWrtp using compression,
+// ie. to compare codecs before choosing one.
+func EstimateParquetSizeCompressed(sc *arrow.Schema, sampleRecords []arrow.Record, compression compress.Compression) (float64, error) {
+	return estimateParquetSize(sc, sampleRecords, NewWriterProperties(parquet.WithCompression(compression)))
+}
+
+func estimateParquetSize(sc *arrow.Schema, sampleRecords []arrow.Record, wrtp *parquet.WriterProperties) (float64, error) {
+	var rows int64
+	for _, rec := range sampleRecords {
+		rows += rec.NumRows()
+	}
+	if rows == 0 {
+		return 0, fmt.Errorf("failed to estimate parquet size: no sample rows")
+	}
+	overhead, err := writtenSize(sc, nil, wrtp)
+	if err != nil {
+		return 0, err
+	}
+	total, err := writtenSize(sc, sampleRecords, wrtp)
+	if err != nil {
+		return 0, err
+	}
+	return float64(total-overhead) / float64(rows), nil
+}
+
+// writtenSize returns the size of a Parquet file containing recs.
+func writtenSize(sc *arrow.Schema, recs []arrow.Record, wrtp *parquet.WriterProperties) (int64, error) {
+	var buf bytes.Buffer
+	pw, _, err := NewParquetWriterTo(sc, wrtp, &buf)
+	if err != nil {
+		return 0, err
+	}
+	for _, rec := range recs {
+		if err := pw.WriteRecord(rec); err != nil {
+			pw.Close()
+			return 0, err
+		}
+	}
+	if err := pw.Close(); err != nil {
+		return 0, err
+	}
+	return int64(buf.Len()), nil
+}
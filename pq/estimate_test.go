@@ -0,0 +1,37 @@
+package pq
+
+import (
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet/compress"
+)
+
+func TestEstimateParquetSizeFractional(t *testing.T) {
+	sc := arrow.NewSchema([]arrow.Field{{Name: "b", Type: arrow.FixedWidthTypes.Boolean}}, nil)
+	bld := array.NewRecordBuilder(memory.DefaultAllocator, sc)
+	defer bld.Release()
+	for range 10000 {
+		bld.Field(0).(*array.BooleanBuilder).Append(true)
+	}
+	rec := bld.NewRecord()
+	defer rec.Release()
+
+	perRow, err := EstimateParquetSize(sc, []arrow.Record{rec})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a constant boolean column compresses to a small fraction of a byte per row
+	if perRow <= 0 || perRow >= 1 {
+		t.Errorf("EstimateParquetSize() = %v bytes per row, want a fraction of a byte", perRow)
+	}
+	perRow, err = EstimateParquetSizeCompressed(sc, []arrow.Record{rec}, compress.Codecs.Snappy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perRow <= 0 || perRow >= 1 {
+		t.Errorf("EstimateParquetSizeCompressed() = %v bytes per row, want a fraction of a byte", perRow)
+	}
+}
@@ -0,0 +1,195 @@
+package pq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet/file"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+)
+
+const (
+	defaultReadBatchSize = 1024
+)
+
+type ParquetReader struct {
+	pqrdr  *file.Reader
+	fr     *pqarrow.FileReader
+	rr     pqarrow.RecordReader
+	sc     *arrow.Schema
+	rec    arrow.Record
+	slice  arrow.Record
+	skip   int64
+	count  int
+	closed bool
+}
+
+//	NewParquetReader opens the Parquet file at path to read back its records.
+//
+// It can be used to verify a file written with ParquetWriter against its JSON source.
+//
+// Returns a ParquetReader and an error. The error will be non-nil if:
+// - Failed to open the Parquet file.
+// - Failed to get the Arrow schema of the Parquet file.
+// - Failed to create the record reader.
+//
+// Example:
+// ```go
+// pr, err := NewParquetReader("out.parquet")
+//
+//	if err != nil {
+//	  log.Fatal(err)
+//	}
+//
+// defer pr.Close()
+//
+//	for pr.Next() {
+//	  rec := pr.Record()
+//	  ...
+//	}
+//
+// ```
+func NewParquetReader(path string) (*ParquetReader, error) {
+	pqrdr, err := file.OpenParquetFile(path, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open parquet file: %w", err)
+	}
+	fr, err := pqarrow.NewFileReader(pqrdr, pqarrow.ArrowReadProperties{BatchSize: defaultReadBatchSize}, memory.DefaultAllocator)
+	if err != nil {
+		pqrdr.Close()
+		return nil, fmt.Errorf("failed to create parquet file reader: %w", err)
+	}
+	sc, err := fr.Schema()
+	if err != nil {
+		pqrdr.Close()
+		return nil, fmt.Errorf("failed to get arrow schema: %w", err)
+	}
+	rr, err := fr.GetRecordReader(context.Background(), nil, nil)
+	if err != nil {
+		pqrdr.Close()
+		return nil, fmt.Errorf("failed to create record reader: %w", err)
+	}
+
+	return &ParquetReader{pqrdr: pqrdr, fr: fr, rr: rr, sc: sc}, nil
+}
+
+// Schema returns the Arrow schema of the Parquet file, which is the schema of the
+// records written if the file was written with ParquetWriter.
+func (pr *ParquetReader) Schema() *arrow.Schema {
+	return pr.sc
+}
+
+// NumRows returns the number of rows in the Parquet file.
+func (pr *ParquetReader) NumRows() int64 {
+	return pr.pqrdr.NumRows()
+}
+
+// Next advances to the next record, returning false when the file is exhausted
+// or an error occurred, see Err.
+func (pr *ParquetReader) Next() bool {
+	if pr.closed {
+		return false
+	}
+	pr.releaseSlice()
+	for pr.rr.Next() {
+		rec := pr.rr.Record()
+		if pr.skip >= rec.NumRows() {
+			pr.skip -= rec.NumRows()
+			continue
+		}
+		if pr.skip > 0 {
+			pr.slice = rec.NewSlice(pr.skip, rec.NumRows())
+			pr.skip = 0
+			rec = pr.slice
+		}
+		pr.rec = rec
+		pr.count++
+		return true
+	}
+	pr.rec = nil
+	return false
+}
+
+// releaseSlice releases the record sliced by the last seek, if any.
+func (pr *ParquetReader) releaseSlice() {
+	if pr.slice != nil {
+		pr.slice.Release()
+		pr.slice = nil
+	}
+}
+
+// Record returns the current record, which is only valid until the next call to Next.
+// It must be retained to be kept.
+func (pr *ParquetReader) Record() arrow.Record {
+	return pr.rec
+}
+
+// SeekToRow moves the reader so that the next record starts at row, ie. to sample
+// a record from the middle of the file. The record reader is reopened over the row
+// groups at or after row and the rows before it in its first row group are skipped.
+func (pr *ParquetReader) SeekToRow(row int64) error {
+	if pr.closed {
+		return fmt.Errorf("failed to seek to row %d: reader closed", row)
+	}
+	if row < 0 || row >= pr.NumRows() {
+		return fmt.Errorf("row %d out of range [0, %d)", row, pr.NumRows())
+	}
+	var start int64
+	groups := []int{}
+	skip := int64(-1)
+	for i := 0; i < pr.pqrdr.NumRowGroups(); i++ {
+		n := pr.pqrdr.MetaData().RowGroup(i).NumRows()
+		if skip < 0 && row < start+n {
+			skip = row - start
+		}
+		if skip >= 0 {
+			groups = append(groups, i)
+		}
+		start += n
+	}
+	rr, err := pr.fr.GetRecordReader(context.Background(), nil, groups)
+	if err != nil {
+		return fmt.Errorf("failed to seek to row %d: %w", row, err)
+	}
+	pr.releaseSlice()
+	pr.rec = nil
+	pr.rr.Release()
+	pr.rr = rr
+	pr.skip = skip
+	return nil
+}
+
+// Err returns the error which stopped Next, if any.
+func (pr *ParquetReader) Err() error {
+	if err := pr.rr.Err(); !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
+
+// RecordCount returns the total number of records read.
+func (pr *ParquetReader) RecordCount() int {
+	return pr.count
+}
+
+//	Close closes the Parquet reader.
+//
+// Returns an error if failed to close the Parquet file.
+func (pr *ParquetReader) Close() error {
+	if pr.closed {
+		return nil
+	}
+	pr.closed = true
+	pr.rec = nil
+	pr.releaseSlice()
+	pr.rr.Release()
+	if err := pr.pqrdr.Close(); err != nil {
+		return fmt.Errorf("failed to close parquet reader: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package pq
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet"
+)
+
+func TestParquetReaderSeekToRow(t *testing.T) {
+	sc := arrow.NewSchema([]arrow.Field{{Name: "n", Type: arrow.PrimitiveTypes.Int64}}, nil)
+	path := filepath.Join(t.TempDir(), "seek.parquet")
+	pw, _, err := NewParquetWriter(sc, NewWriterProperties(parquet.WithMaxRowGroupLength(4)), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bld := array.NewRecordBuilder(memory.DefaultAllocator, sc)
+	defer bld.Release()
+	for i := int64(0); i < 10; i++ {
+		bld.Field(0).(*array.Int64Builder).Append(i)
+	}
+	rec := bld.NewRecord()
+	if err := pw.WriteRecord(rec); err != nil {
+		t.Fatal(err)
+	}
+	rec.Release()
+	if err := pw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	pr, err := NewParquetReader(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pr.Close()
+	for _, row := range []int64{6, 0, 9, 4} {
+		if err := pr.SeekToRow(row); err != nil {
+			t.Fatal(err)
+		}
+		var got []int64
+		for pr.Next() {
+			got = append(got, pr.Record().Column(0).(*array.Int64).Int64Values()...)
+		}
+		if err := pr.Err(); err != nil {
+			t.Fatal(err)
+		}
+		if int64(len(got)) != 10-row || got[0] != row {
+			t.Errorf("SeekToRow(%d): got %v", row, got)
+		}
+	}
+	if err := pr.SeekToRow(10); err == nil {
+		t.Error("SeekToRow(10): expected out of range error")
+	}
+}
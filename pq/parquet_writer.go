@@ -2,7 +2,9 @@ package pq
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"slices"
 
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
@@ -18,20 +20,30 @@ const (
 )
 
 var (
-	DefaultWrtp = parquet.NewWriterProperties(
+	defaultWriterProperties = []parquet.WriterProperty{
 		parquet.WithDictionaryDefault(true),
 		parquet.WithVersion(parquet.V2_LATEST),
 		parquet.WithCompression(compress.Codecs.Zstd),
 		parquet.WithStats(true),
 		parquet.WithRootName("bodkin"),
-	)
+	}
+	DefaultWrtp = parquet.NewWriterProperties(defaultWriterProperties...)
 )
 
+// NewWriterProperties returns the DefaultWrtp writer properties with opts applied,
+// eg. parquet.WithRootName to change the root name of the Parquet schema.
+func NewWriterProperties(opts ...parquet.WriterProperty) *parquet.WriterProperties {
+	return parquet.NewWriterProperties(append(slices.Clone(defaultWriterProperties), opts...)...)
+}
+
 type ParquetWriter struct {
 	destFile *os.File
 	pqwrt    *pqarrow.FileWriter
 	sc       *arrow.Schema
 	count    int
+	mem      memory.Allocator
+	// decode run-end encoded columns
+	runEnds bool
 }
 
 //	NewParquetWriter creates a new ParquetWriter.
@@ -54,7 +66,8 @@ type ParquetWriter struct {
 //
 // ```
 func NewParquetWriter(sc *arrow.Schema, wrtp *parquet.WriterProperties, path string) (*ParquetWriter, *schema.Schema, error) {
-	pqschema, err := pqarrow.ToParquet(sc, wrtp, pqarrow.DefaultWriterProps())
+	plain, _ := decodedSchema(sc)
+	pqschema, err := pqarrow.ToParquet(plain, wrtp, pqarrow.DefaultWriterProps())
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to get parquet schema: %w", err)
 	}
@@ -63,13 +76,48 @@ func NewParquetWriter(sc *arrow.Schema, wrtp *parquet.WriterProperties, path str
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to create destination file: %w", err)
 	}
+	pw, err := newParquetWriter(sc, wrtp, destFile)
+	if err != nil {
+		destFile.Close()
+		return nil, nil, err
+	}
+	pw.destFile = destFile
+
+	return pw, pqschema, nil
+}
+
+// NewParquetWriterTo creates a new ParquetWriter writing to w, ie. a bytes.Buffer
+// or a network stream.
+//
+// sc is the Arrow schema to use for writing records.
+// wrtp are the Parquet writer properties to use.
+//
+// If w implements io.Closer it is closed when the ParquetWriter is closed.
+func NewParquetWriterTo(sc *arrow.Schema, wrtp *parquet.WriterProperties, w io.Writer) (*ParquetWriter, *schema.Schema, error) {
+	plain, _ := decodedSchema(sc)
+	pqschema, err := pqarrow.ToParquet(plain, wrtp, pqarrow.DefaultWriterProps())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get parquet schema: %w", err)
+	}
+	pw, err := newParquetWriter(sc, wrtp, w)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return pw, pqschema, nil
+}
+
+// newParquetWriter returns a ParquetWriter writing to w. Run-end encoded columns of sc
+// are written decoded.
+func newParquetWriter(sc *arrow.Schema, wrtp *parquet.WriterProperties, w io.Writer) (*ParquetWriter, error) {
+	sc, runEnds := decodedSchema(sc)
 	artp := pqarrow.NewArrowWriterProperties(pqarrow.WithStoreSchema())
-	pqwrt, err := pqarrow.NewFileWriter(sc, destFile, wrtp, artp)
+	pqwrt, err := pqarrow.NewFileWriter(sc, w, wrtp, artp)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to create parquet writer: %w", err)
+		return nil, fmt.Errorf("failed to create parquet writer: %w", err)
 	}
 
-	return &ParquetWriter{destFile: destFile, pqwrt: pqwrt, sc: sc}, pqschema, nil
+	return &ParquetWriter{pqwrt: pqwrt, sc: sc, mem: memory.DefaultAllocator, runEnds: runEnds}, nil
 }
 
 //	Write writes a single record to the Parquet file.
@@ -93,7 +141,7 @@ func NewParquetWriter(sc *arrow.Schema, wrtp *parquet.WriterProperties, path str
 //
 // ```
 func (pw *ParquetWriter) Write(jsonData []byte) error {
-	recbld := array.NewRecordBuilder(memory.DefaultAllocator, pw.sc)
+	recbld := array.NewRecordBuilder(pw.mem, pw.sc)
 	defer recbld.Release()
 
 	err := recbld.UnmarshalJSON(jsonData)
@@ -116,7 +164,16 @@ func (pw *ParquetWriter) Write(jsonData []byte) error {
 	return nil
 }
 
+// WriteRecord writes rec to the Parquet file, decoding its run-end encoded columns.
 func (pw *ParquetWriter) WriteRecord(rec arrow.Record) error {
+	if pw.runEnds {
+		dec, err := decodeRunEnds(pw.mem, rec, pw.sc)
+		if err != nil {
+			return err
+		}
+		defer dec.Release()
+		rec = dec
+	}
 	err := pw.pqwrt.WriteBuffered(rec)
 	if err != nil {
 		return fmt.Errorf("failed to write to parquet: %w", err)
@@ -130,6 +187,12 @@ func (pw *ParquetWriter) WriteRecord(rec arrow.Record) error {
 	return nil
 }
 
+// AppendKeyValueMetadata adds a key-value pair to the Parquet file's metadata, eg. for
+// lineage such as the source feed or ingestion time. It must be called before Close.
+func (pw *ParquetWriter) AppendKeyValueMetadata(key, value string) error {
+	return pw.pqwrt.AppendKeyValueMetadata(key, value)
+}
+
 // RecordCount returns the total number of records written.
 func (pw *ParquetWriter) RecordCount() int {
 	return pw.count
@@ -0,0 +1,91 @@
+package pq
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/compute"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// decodedSchema returns sc with its top-level run-end encoded columns replaced by their
+// value type, which Parquet can store, and whether any were replaced.
+func decodedSchema(sc *arrow.Schema) (*arrow.Schema, bool) {
+	fields := sc.Fields()
+	encoded := false
+	for i, f := range fields {
+		if ree, ok := f.Type.(*arrow.RunEndEncodedType); ok {
+			fields[i].Type = ree.Encoded()
+			fields[i].Nullable = true
+			encoded = true
+		}
+	}
+	if !encoded {
+		return sc, false
+	}
+	md := sc.Metadata()
+	return arrow.NewSchema(fields, &md), true
+}
+
+// decodeRunEnds returns rec with its top-level run-end encoded columns decoded, as a
+// record of schema sc, allocating the decoded columns with mem. The returned record
+// must be released.
+func decodeRunEnds(mem memory.Allocator, rec arrow.Record, sc *arrow.Schema) (arrow.Record, error) {
+	cols := make([]arrow.Array, rec.NumCols())
+	defer func() {
+		for _, c := range cols {
+			if c != nil {
+				c.Release()
+			}
+		}
+	}()
+	for i, c := range rec.Columns() {
+		ree, ok := c.(*array.RunEndEncoded)
+		if !ok {
+			c.Retain()
+			cols[i] = c
+			continue
+		}
+		d, err := decodeRunEndArray(mem, ree)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode run-end encoded column %s: %w", rec.ColumnName(i), err)
+		}
+		cols[i] = d
+	}
+	return array.NewRecord(sc, cols, rec.NumRows()), nil
+}
+
+// decodeRunEndArray returns the values of ree repeated for the length of their runs,
+// taking each run's value by its physical index.
+func decodeRunEndArray(mem memory.Allocator, ree *array.RunEndEncoded) (arrow.Array, error) {
+	ends := ree.LogicalRunEndsArray(mem)
+	defer ends.Release()
+	idx := array.NewInt32Builder(mem)
+	defer idx.Release()
+	idx.Reserve(ree.Len())
+	offset, row := ree.GetPhysicalOffset(), 0
+	for p := 0; p < ends.Len(); p++ {
+		end := min(runEnd(ends, p), ree.Len())
+		for ; row < end; row++ {
+			idx.UnsafeAppend(int32(offset + p))
+		}
+	}
+	indices := idx.NewArray()
+	defer indices.Release()
+	return compute.TakeArray(compute.WithAllocator(context.Background(), mem), ree.Values(), indices)
+}
+
+// runEnd returns the run end at index i of ends, an Int16, Int32 or Int64 array.
+func runEnd(ends arrow.Array, i int) int {
+	switch e := ends.(type) {
+	case *array.Int16:
+		return int(e.Value(i))
+	case *array.Int32:
+		return int(e.Value(i))
+	case *array.Int64:
+		return int(e.Value(i))
+	}
+	return 0
+}
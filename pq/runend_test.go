@@ -0,0 +1,61 @@
+package pq
+
+import (
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+func TestDecodeRunEndArray(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	bld := array.NewRunEndEncodedBuilder(mem, arrow.PrimitiveTypes.Int32, arrow.BinaryTypes.String)
+	defer bld.Release()
+	values := bld.ValueBuilder().(*array.StringBuilder)
+	for _, run := range []struct {
+		v string
+		n uint64
+	}{{"a", 3}, {"", 0}, {"b", 1}, {"c", 4}} {
+		if run.n == 0 {
+			bld.AppendNull()
+			continue
+		}
+		bld.Append(run.n)
+		values.Append(run.v)
+	}
+	ree := bld.NewArray().(*array.RunEndEncoded)
+	defer ree.Release()
+
+	tests := []struct {
+		name       string
+		start, end int64
+		want       []string
+	}{
+		{"whole", 0, int64(ree.Len()), []string{"a", "a", "a", "", "b", "c", "c", "c", "c"}},
+		{"slice", 2, 6, []string{"a", "", "b", "c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := array.NewSlice(ree, tt.start, tt.end).(*array.RunEndEncoded)
+			defer s.Release()
+			got, err := decodeRunEndArray(mem, s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer got.Release()
+			str := got.(*array.String)
+			if str.Len() != len(tt.want) {
+				t.Fatalf("decoded %v, want %q", got, tt.want)
+			}
+			for i, w := range tt.want {
+				if w == "" && !str.IsNull(i) || w != "" && str.Value(i) != w {
+					t.Errorf("decoded %v, want %q", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package bodkin
+
+// FieldPresence counts how a field appeared in the inputs unified to date, as collected
+// with WithDistinguishAbsentFromNull.
+type FieldPresence struct {
+	// Number of inputs in which the field had a non-null value.
+	Values int
+	// Number of inputs in which the field was present with a null value.
+	Nulls int
+	// Number of inputs in which the field's key was missing, including inputs
+	// missing one of its parents.
+	Absent int
+}
+
+// presence accumulates the presence of a field, counting each input once.
+type presence struct {
+	values, nulls int
+	// input is the number of the last input the field was seen in, null whether it
+	// was only seen as null in it so far.
+	input int
+	null  bool
+}
+
+// FieldPresence returns how each field seen to date appeared in the inputs unified
+// with Unify, keyed by dotpath, if WithDistinguishAbsentFromNull is set.
+// A field inside a list is counted as having a value in an input if any of its
+// elements had one.
+func (u *Bodkin) FieldPresence() map[string]FieldPresence {
+	fp := make(map[string]FieldPresence, len(u.presence))
+	for p, s := range u.presence {
+		fp[p] = FieldPresence{Values: s.values, Nulls: s.nulls, Absent: u.presenceInputs - s.values - s.nulls}
+	}
+	return fp
+}
+
+// observePresence records that the field at dotpath was present in the current input,
+// with a null value if null is set.
+func (u *Bodkin) observePresence(dotpath string, null bool) {
+	s, ok := u.presence[dotpath]
+	if !ok {
+		s = new(presence)
+		u.presence[dotpath] = s
+	}
+	switch {
+	case s.input != u.presenceInputs:
+		s.input = u.presenceInputs
+		s.null = null
+		if null {
+			s.nulls++
+		} else {
+			s.values++
+		}
+	case s.null && !null:
+		s.null = false
+		s.nulls--
+		s.values++
+	}
+}
+
+// nullOnly reports whether the field at dotpath was never seen with a value, in the
+// inputs in which it was present.
+func (u *Bodkin) nullOnly(dotpath string) bool {
+	s, ok := u.presence[dotpath]
+	return ok && s.values == 0 && s.nulls > 0
+}
@@ -0,0 +1,117 @@
+package reader
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+// AvroLogicalTypeMap describes the encoding of the values of Avro logical types loaded
+// from DataSourceAvro input, so that they are converted to the Arrow types of their
+// columns rather than loaded as is, see WithAvroLogicalTypeMap.
+// Columns are keyed by dotpath, ie. "$event.ts".
+type AvroLogicalTypeMap struct {
+	// TimestampUnit is the unit of the longs of Avro timestamps, ie. arrow.Millisecond
+	// for timestamp-millis. They are converted to the unit of their timestamp column.
+	// If nil, the timestamps of columns not keyed by TimestampUnits are loaded as is.
+	TimestampUnit *arrow.TimeUnit
+	// TimestampUnits overrides TimestampUnit for the columns it keys.
+	TimestampUnits map[string]arrow.TimeUnit
+	// DecimalScales are the scales of the Avro decimals of the columns it keys, whose
+	// unscaled integers are rescaled to the scale of their decimal column. The decimals
+	// of other columns are taken to have the scale of their column.
+	DecimalScales map[string]int32
+}
+
+// DefaultAvroLogicalTypeMap loads Avro timestamp-micros longs into timestamp columns of
+// any unit.
+var DefaultAvroLogicalTypeMap = AvroLogicalTypeMap{TimestampUnit: func() *arrow.TimeUnit {
+	unit := arrow.Microsecond
+	return &unit
+}()}
+
+// wrap returns fn converting the Avro logical type values of field f to its Arrow type.
+func (m *AvroLogicalTypeMap) wrap(f *fieldPos, field arrow.Field, fn func(data any) error) func(data any) error {
+	switch dt := field.Type.(type) {
+	case *arrow.TimestampType:
+		from, ok := m.TimestampUnits[f.dotPath()]
+		if !ok && m.TimestampUnit != nil {
+			from, ok = *m.TimestampUnit, true
+		}
+		if !ok || from == dt.Unit {
+			return fn
+		}
+		return func(data any) error {
+			return fn(convertAvroLong(data, from, dt.Unit))
+		}
+	case arrow.DecimalType:
+		scale, ok := m.DecimalScales[f.dotPath()]
+		if !ok {
+			return fn
+		}
+		return func(data any) error {
+			if b, ok := avroBytes(data); ok {
+				// loaded as a decimal string, rescaled to the column's scale
+				data = decimalString(avroDecimalInt(b), scale)
+			}
+			return fn(data)
+		}
+	}
+	return fn
+}
+
+// convertAvroLong returns data, an Avro long or a union of one, converted from unit
+// from to unit to.
+func convertAvroLong(data any, from, to arrow.TimeUnit) any {
+	if m, ok := data.(map[string]any); ok {
+		data = m["long"]
+	}
+	v, ok := data.(int64)
+	if !ok {
+		return data
+	}
+	fm, tm := int64(from.Multiplier()), int64(to.Multiplier())
+	if fm >= tm {
+		return v * (fm / tm)
+	}
+	return v / (tm / fm)
+}
+
+// avroBytes returns the bytes of data, Avro bytes or a union of them.
+func avroBytes(data any) ([]byte, bool) {
+	if m, ok := data.(map[string]any); ok {
+		data = m["bytes"]
+	}
+	b, ok := data.([]byte)
+	return b, ok
+}
+
+// avroDecimalInt returns the unscaled integer of an Avro decimal, a big-endian two's
+// complement integer.
+func avroDecimalInt(b []byte) *big.Int {
+	n := new(big.Int).SetBytes(b)
+	if len(b) > 0 && b[0]&0x80 != 0 {
+		n.Sub(n, new(big.Int).Lsh(big.NewInt(1), uint(len(b)*8)))
+	}
+	return n
+}
+
+// decimalString returns the decimal number of unscaled integer n and scale, ie. 12345
+// and 2 is "123.45".
+func decimalString(n *big.Int, scale int32) string {
+	s := new(big.Int).Abs(n).String()
+	switch {
+	case scale < 0:
+		s += strings.Repeat("0", int(-scale))
+	case scale > 0:
+		if len(s) <= int(scale) {
+			s = strings.Repeat("0", int(scale)-len(s)+1) + s
+		}
+		s = s[:len(s)-int(scale)] + "." + s[len(s)-int(scale):]
+	}
+	if n.Sign() < 0 {
+		s = "-" + s
+	}
+	return s
+}
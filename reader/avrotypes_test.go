@@ -0,0 +1,39 @@
+package reader
+
+import (
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+func TestAvroLogicalTypeMapTimestampUnit(t *testing.T) {
+	root := newFieldPos()
+	f := root.newChild("ts", nil, arrow.Metadata{})
+	field := arrow.Field{Name: "ts", Type: &arrow.TimestampType{Unit: arrow.Microsecond}}
+	milli := arrow.Millisecond
+	tests := []struct {
+		name string
+		m    AvroLogicalTypeMap
+		want any
+	}{
+		{"zero value loads as is", AvroLogicalTypeMap{}, int64(1500)},
+		{"default", DefaultAvroLogicalTypeMap, int64(1500)},
+		{"unit", AvroLogicalTypeMap{TimestampUnit: &milli}, int64(1500000)},
+		{"column unit", AvroLogicalTypeMap{TimestampUnits: map[string]arrow.TimeUnit{"$ts": arrow.Millisecond}}, int64(1500000)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got any
+			fn := tt.m.wrap(f, field, func(data any) error {
+				got = data
+				return nil
+			})
+			if err := fn(int64(1500)); err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("loaded %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package reader
+
+import (
+	"bytes"
+
+	json "github.com/goccy/go-json"
+)
+
+// BatchDecoder decodes a batch of json datum to maps with InputMap semantics, but
+// with a single stream decoder over a scratch buffer reused from batch to batch,
+// instead of a reader and decoder per datum.
+// The zero value is ready to use; a BatchDecoder must not be used concurrently.
+type BatchDecoder struct {
+	buf  []byte
+	segs [][2]int
+	rd   bytes.Reader
+	dec  *json.Decoder
+	m    map[string]any
+	// buf offset of the decoder's input and of the next datum it will decode
+	base, pos int
+}
+
+// Reset empties the batch, keeping the scratch buffer for the next one.
+func (b *BatchDecoder) Reset() {
+	b.buf = b.buf[:0]
+	b.segs = b.segs[:0]
+	b.dec = nil
+}
+
+// Add copies datum to the batch and returns its index, for Datum and Decode.
+func (b *BatchDecoder) Add(datum []byte) int {
+	start := len(b.buf)
+	b.buf = append(b.buf, datum...)
+	b.segs = append(b.segs, [2]int{start, len(b.buf)})
+	// keeps datum apart in the stream, ie. two numbers
+	b.buf = append(b.buf, '\n')
+	return len(b.segs) - 1
+}
+
+// Datum returns the datum at index i, it is only valid until the next Reset.
+func (b *BatchDecoder) Datum(i int) []byte {
+	return b.buf[b.segs[i][0]:b.segs[i][1]]
+}
+
+// Decode decodes the datum at index i. Datum are expected to be decoded in the
+// order they were added, skipping some only restarts the stream decoder.
+// The map returned is reused by the next call to Decode.
+func (b *BatchDecoder) Decode(i int) (map[string]any, error) {
+	start, end := b.segs[i][0], b.segs[i][1]
+	if b.dec == nil || b.pos != start {
+		b.rd.Reset(b.buf[start:])
+		b.dec = json.NewDecoder(&b.rd)
+		b.dec.UseNumber()
+		b.base = start
+	}
+	if b.m == nil {
+		b.m = map[string]any{}
+	}
+	clear(b.m)
+	err := b.dec.Decode(&b.m)
+	// The value must end within the datum, followed by whitespace only: a datum
+	// which is invalid, empty or has trailing data is decoded on its own so that
+	// it fails or succeeds exactly as with InputMap, and the stream restarts after it.
+	off := b.base + int(b.dec.InputOffset())
+	if err != nil || off > end || len(bytes.TrimSpace(b.buf[off:end])) != 0 {
+		b.dec = nil
+		return InputMap(b.Datum(i))
+	}
+	b.pos = end + 1
+	return b.m, nil
+}
@@ -0,0 +1,23 @@
+package reader
+
+import (
+	"strings"
+	"unicode"
+)
+
+// IsBlank reports whether s is empty or made up only of Unicode white space, as
+// defined by unicode.IsSpace, ie. spaces, tabs, newlines, carriage returns, form feeds,
+// U+0085 (NEL) and U+00A0 (NBSP).
+func IsBlank(s string) bool {
+	return strings.TrimFunc(s, unicode.IsSpace) == ""
+}
+
+// blankAsNull wraps fn so that blank string values are appended as null.
+func blankAsNull(fn func(val interface{}) error) func(val interface{}) error {
+	return func(data interface{}) error {
+		if s, ok := data.(string); ok && IsBlank(s) {
+			data = nil
+		}
+		return fn(data)
+	}
+}
@@ -0,0 +1,15 @@
+package reader
+
+// JSONBlobKey is the field metadata key marking a string column which holds the JSON
+// text of its input values, whatever their structure, ie. {"a":1}, [1,2] or "a".
+const JSONBlobKey = "bodkin.json"
+
+// jsonBlob returns fn appending the JSON text of its data.
+func jsonBlob(fn func(data any) error) func(data any) error {
+	return func(data any) error {
+		if data == nil {
+			return fn(nil)
+		}
+		return fn(jsonString(data))
+	}
+}
@@ -0,0 +1,85 @@
+package reader
+
+import (
+	"strings"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+// keyFold holds the input names of the fields of a struct, keyed by their lower case
+// form, and the keyFold of their nested structs, keyed by input name.
+type keyFold struct {
+	names    map[string]string
+	children map[string]*keyFold
+}
+
+// newKeyFold returns the keyFold of fields, nil if there are none.
+func newKeyFold(fields []arrow.Field) *keyFold {
+	if len(fields) == 0 {
+		return nil
+	}
+	kf := &keyFold{names: make(map[string]string), children: make(map[string]*keyFold)}
+	for _, f := range fields {
+		name := f.Name
+		if orig, ok := f.Metadata.GetValue(OriginalNameKey); ok {
+			name = orig
+		}
+		kf.names[strings.ToLower(name)] = name
+		if st := structOf(f.Type); st != nil {
+			kf.children[name] = newKeyFold(st.Fields())
+		}
+	}
+	return kf
+}
+
+// structOf returns the struct type of dt or of the elements of its lists, nil if it
+// has none.
+func structOf(dt arrow.DataType) *arrow.StructType {
+	for {
+		switch t := dt.(type) {
+		case *arrow.StructType:
+			return t
+		case *arrow.MapType:
+			// map keys are data, not field names
+			return nil
+		case arrow.ListLikeType:
+			dt = t.Elem()
+		default:
+			return nil
+		}
+	}
+}
+
+// fold returns data with the keys of its objects matching a field name under
+// case-folding renamed to the field's input name. If an object has several such keys
+// for a field, the one spelled as the field's name is loaded, or else the smallest.
+func (kf *keyFold) fold(data any) any {
+	if kf == nil {
+		return data
+	}
+	switch t := data.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(t))
+		from := make(map[string]string, len(t))
+		for k, v := range t {
+			name, ok := kf.names[strings.ToLower(k)]
+			if !ok {
+				out[k] = v
+				continue
+			}
+			if prev, ok := from[name]; ok && (prev == name || k != name && prev < k) {
+				continue
+			}
+			from[name] = k
+			out[name] = kf.children[name].fold(v)
+		}
+		return out
+	case []any:
+		out := make([]any, len(t))
+		for i, v := range t {
+			out[i] = kf.fold(v)
+		}
+		return out
+	}
+	return data
+}
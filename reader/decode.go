@@ -0,0 +1,396 @@
+package reader
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+)
+
+var (
+	ErrDecodeMismatch = errors.New("schema and struct mismatch")
+)
+
+var (
+	timeType     = reflect.TypeFor[time.Time]()
+	durationType = reflect.TypeFor[time.Duration]()
+)
+
+// DecodeInto returns an iterator decoding each row of the records read by r into
+// a value of type T, which must be a struct or a pointer to a struct.
+//
+// Struct fields are matched to columns by name, using the same mapstructure, json and
+// bodkin tags as InputMap, and falling back to a case-insensitive match.
+// Nested structs, slices, maps and pointers are supported, null values leave pointer
+// fields nil and other fields at their zero value. Timestamp, date and time columns can be
+// decoded to time.Time, duration columns to time.Duration, and any column can be
+// decoded to an interface{} field. Timestamp, date and duration columns can also be
+// decoded to a string field, formatted as RFC 3339, 2006-01-02 and time.Duration.String.
+//
+// An error is yielded and iteration stops if a struct field has no matching column or
+// a column cannot be decoded to its field's type, or if r encountered an error.
+func DecodeInto[T any](r *DataReader) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		var d decoder
+		for r.Next() {
+			rec := r.Record()
+			fields := rec.Schema().Fields()
+			// columns are matched to struct fields once per record
+			clear(d.plans)
+			for i := 0; i < int(rec.NumRows()); i++ {
+				var v T
+				err := d.decodeStruct(reflect.ValueOf(&v).Elem(), rec.Schema(), fields, rec.Column, i)
+				if err != nil {
+					yield(zero, err)
+					return
+				}
+				if !yield(v, nil) {
+					return
+				}
+			}
+		}
+		if err := r.Err(); err != nil {
+			yield(zero, err)
+		}
+	}
+}
+
+// errMismatch is returned by the set functions when a value cannot be set to dst,
+// decodeValue replaces it by an ErrDecodeMismatch naming the types.
+var errMismatch = errors.New("mismatch")
+
+// decoder decodes rows into Go values, caching the columns matched to the fields
+// of each struct type.
+type decoder struct {
+	plans map[planKey]*structPlan
+}
+
+// planKey identifies the columns of a struct type: src is the record's schema for
+// top-level columns, the arrow.StructType otherwise.
+type planKey struct {
+	typ reflect.Type
+	src any
+}
+
+// structPlan is the column of each decoded field of a struct type.
+type structPlan struct {
+	fields []planField
+}
+
+type planField struct {
+	index  int
+	column int
+	// squash decodes an embedded struct from the same columns.
+	squash bool
+	// err is returned when the field is decoded, ie. it has no column.
+	err error
+}
+
+// plan returns the columns of the fields of struct type t among fields.
+func (d *decoder) plan(t reflect.Type, src any, fields []arrow.Field) *structPlan {
+	k := planKey{typ: t, src: src}
+	if p, ok := d.plans[k]; ok {
+		return p
+	}
+	p := &structPlan{}
+	for j := 0; j < t.NumField(); j++ {
+		sf := t.Field(j)
+		if !sf.IsExported() {
+			continue
+		}
+		info := getTagInfo(sf)
+		switch {
+		case info.skip:
+			continue
+		case info.squash:
+			p.fields = append(p.fields, planField{index: j, squash: true})
+			continue
+		}
+		pf := planField{index: j, column: findColumn(fields, info.name)}
+		if pf.column < 0 {
+			pf.err = fmt.Errorf("%w : no column for field %s", ErrDecodeMismatch, sf.Name)
+		}
+		p.fields = append(p.fields, pf)
+	}
+	if d.plans == nil {
+		d.plans = make(map[planKey]*structPlan)
+	}
+	d.plans[k] = p
+	return p
+}
+
+// decodeStruct decodes row i of the columns described by fields into the struct dst.
+func (d *decoder) decodeStruct(dst reflect.Value, src any, fields []arrow.Field, column func(int) arrow.Array, i int) error {
+	if dst.Kind() == reflect.Pointer {
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		dst = dst.Elem()
+	}
+	if dst.Kind() != reflect.Struct {
+		return fmt.Errorf("%w : %v is not a struct", ErrDecodeMismatch, dst.Type())
+	}
+	for _, pf := range d.plan(dst.Type(), src, fields).fields {
+		switch {
+		case pf.err != nil:
+			return pf.err
+		case pf.squash:
+			if err := d.decodeStruct(dst.Field(pf.index), src, fields, column, i); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := d.decodeValue(dst.Field(pf.index), column(pf.column), i); err != nil {
+			return fmt.Errorf("%s : %w", fields[pf.column].Name, err)
+		}
+	}
+	return nil
+}
+
+// findColumn returns the index of the field named name, or -1 if it is not found.
+func findColumn(fields []arrow.Field, name string) int {
+	for i, f := range fields {
+		if f.Name == name {
+			return i
+		}
+	}
+	for i, f := range fields {
+		if strings.EqualFold(f.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// decodeValue decodes the value at index i of arr into dst.
+func (d *decoder) decodeValue(dst reflect.Value, arr arrow.Array, i int) error {
+	if arr.IsNull(i) {
+		dst.SetZero()
+		return nil
+	}
+	switch dst.Kind() {
+	case reflect.Pointer:
+		v := reflect.New(dst.Type().Elem())
+		if err := d.decodeValue(v.Elem(), arr, i); err != nil {
+			return err
+		}
+		dst.Set(v)
+		return nil
+	case reflect.Interface:
+		v := arr.GetOneForMarshal(i)
+		if v == nil {
+			dst.SetZero()
+			return nil
+		}
+		if !reflect.TypeOf(v).AssignableTo(dst.Type()) {
+			return fmt.Errorf("%w : cannot assign %T to %v", ErrDecodeMismatch, v, dst.Type())
+		}
+		dst.Set(reflect.ValueOf(v))
+		return nil
+	}
+	if err := d.decodeArray(dst, arr, i); err != errMismatch {
+		return err
+	}
+	return fmt.Errorf("%w : cannot decode %v to %v", ErrDecodeMismatch, arr.DataType(), dst.Type())
+}
+
+// decodeArray decodes the non-null value at index i of arr into dst, returning
+// errMismatch if it cannot be set to dst's type.
+func (d *decoder) decodeArray(dst reflect.Value, arr arrow.Array, i int) error {
+	switch a := arr.(type) {
+	case *array.Boolean:
+		if dst.Kind() != reflect.Bool {
+			return errMismatch
+		}
+		dst.SetBool(a.Value(i))
+	case *array.Int8:
+		return setInt(dst, int64(a.Value(i)))
+	case *array.Int16:
+		return setInt(dst, int64(a.Value(i)))
+	case *array.Int32:
+		return setInt(dst, int64(a.Value(i)))
+	case *array.Int64:
+		return setInt(dst, a.Value(i))
+	case *array.Uint8:
+		return setUint(dst, uint64(a.Value(i)))
+	case *array.Uint16:
+		return setUint(dst, uint64(a.Value(i)))
+	case *array.Uint32:
+		return setUint(dst, uint64(a.Value(i)))
+	case *array.Uint64:
+		return setUint(dst, a.Value(i))
+	case *array.Float32:
+		return setFloat(dst, float64(a.Value(i)))
+	case *array.Float64:
+		return setFloat(dst, a.Value(i))
+	case *array.String:
+		return setString(dst, a.Value(i))
+	case *array.LargeString:
+		return setString(dst, a.Value(i))
+	case *array.Binary:
+		return setBytes(dst, a.Value(i))
+	case *array.FixedSizeBinary:
+		return setBytes(dst, a.Value(i))
+	case *array.Timestamp:
+		unit := a.DataType().(*arrow.TimestampType).Unit
+		return setTime(dst, a.Value(i).ToTime(unit), time.RFC3339Nano, int64(a.Value(i)))
+	case *array.Date32:
+		return setTime(dst, a.Value(i).ToTime(), time.DateOnly, int64(a.Value(i)))
+	case *array.Date64:
+		return setTime(dst, a.Value(i).ToTime(), time.DateOnly, int64(a.Value(i)))
+	case *array.Duration:
+		unit := a.DataType().(*arrow.DurationType).Unit
+		dur := time.Duration(int64(a.Value(i)) * int64(unit.Multiplier()))
+		switch {
+		case dst.Type() == durationType:
+			dst.SetInt(int64(dur))
+			return nil
+		case dst.Kind() == reflect.String:
+			dst.SetString(dur.String())
+			return nil
+		}
+		return setInt(dst, int64(a.Value(i)))
+	case *array.Struct:
+		st := a.DataType().(*arrow.StructType)
+		return d.decodeStruct(dst, st, st.Fields(), a.Field, i)
+	case *array.Map:
+		start, end := a.ValueOffsets(i)
+		return d.decodeMap(dst, a.Keys(), a.Items(), int(start), int(end))
+	case *array.List:
+		start, end := a.ValueOffsets(i)
+		return d.decodeSlice(dst, a.ListValues(), int(start), int(end))
+	case *array.LargeList:
+		start, end := a.ValueOffsets(i)
+		return d.decodeSlice(dst, a.ListValues(), int(start), int(end))
+	default:
+		return errMismatch
+	}
+	return nil
+}
+
+func (d *decoder) decodeSlice(dst reflect.Value, values arrow.Array, start, end int) error {
+	if dst.Kind() != reflect.Slice {
+		return errMismatch
+	}
+	s := reflect.MakeSlice(dst.Type(), end-start, end-start)
+	for j := start; j < end; j++ {
+		if err := d.decodeValue(s.Index(j-start), values, j); err != nil {
+			return err
+		}
+	}
+	dst.Set(s)
+	return nil
+}
+
+func (d *decoder) decodeMap(dst reflect.Value, keys, items arrow.Array, start, end int) error {
+	if dst.Kind() != reflect.Map {
+		return errMismatch
+	}
+	m := reflect.MakeMapWithSize(dst.Type(), end-start)
+	k := reflect.New(dst.Type().Key()).Elem()
+	v := reflect.New(dst.Type().Elem()).Elem()
+	for j := start; j < end; j++ {
+		if err := d.decodeValue(k, keys, j); err != nil {
+			return err
+		}
+		if err := d.decodeValue(v, items, j); err != nil {
+			return err
+		}
+		m.SetMapIndex(k, v)
+	}
+	dst.Set(m)
+	return nil
+}
+
+func setInt(dst reflect.Value, v int64) error {
+	switch dst.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if dst.OverflowInt(v) {
+			return fmt.Errorf("%w : %d overflows %v", ErrDecodeMismatch, v, dst.Type())
+		}
+		dst.SetInt(v)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if v < 0 || dst.OverflowUint(uint64(v)) {
+			return fmt.Errorf("%w : %d overflows %v", ErrDecodeMismatch, v, dst.Type())
+		}
+		dst.SetUint(uint64(v))
+	case reflect.Float32, reflect.Float64:
+		dst.SetFloat(float64(v))
+	default:
+		return errMismatch
+	}
+	return nil
+}
+
+func setUint(dst reflect.Value, v uint64) error {
+	switch dst.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if dst.OverflowUint(v) {
+			return fmt.Errorf("%w : %d overflows %v", ErrDecodeMismatch, v, dst.Type())
+		}
+		dst.SetUint(v)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v > 1<<63-1 || dst.OverflowInt(int64(v)) {
+			return fmt.Errorf("%w : %d overflows %v", ErrDecodeMismatch, v, dst.Type())
+		}
+		dst.SetInt(int64(v))
+	case reflect.Float32, reflect.Float64:
+		dst.SetFloat(float64(v))
+	default:
+		return errMismatch
+	}
+	return nil
+}
+
+func setFloat(dst reflect.Value, v float64) error {
+	switch dst.Kind() {
+	case reflect.Float32, reflect.Float64:
+		dst.SetFloat(v)
+	default:
+		return errMismatch
+	}
+	return nil
+}
+
+func setString(dst reflect.Value, v string) error {
+	switch {
+	case dst.Kind() == reflect.String:
+		dst.SetString(v)
+	case dst.Kind() == reflect.Slice && dst.Type().Elem().Kind() == reflect.Uint8:
+		dst.SetBytes([]byte(v))
+	default:
+		return errMismatch
+	}
+	return nil
+}
+
+func setBytes(dst reflect.Value, v []byte) error {
+	switch {
+	case dst.Kind() == reflect.Slice && dst.Type().Elem().Kind() == reflect.Uint8:
+		dst.SetBytes(append([]byte(nil), v...))
+	case dst.Kind() == reflect.String:
+		dst.SetString(string(v))
+	default:
+		return errMismatch
+	}
+	return nil
+}
+
+// setTime sets dst to t if it is a time.Time, or to the raw value if it is an integer.
+func setTime(dst reflect.Value, t time.Time, layout string, raw int64) error {
+	switch {
+	case dst.Type() == timeType:
+		dst.Set(reflect.ValueOf(t))
+		return nil
+	case dst.Kind() == reflect.String:
+		dst.SetString(t.Format(layout))
+		return nil
+	}
+	return setInt(dst, raw)
+}
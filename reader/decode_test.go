@@ -0,0 +1,129 @@
+package reader
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+func TestDecodeInto(t *testing.T) {
+	type address struct {
+		City string `json:"city"`
+	}
+	type user struct {
+		Name    string   `json:"name"`
+		Age     *int32   `json:"age"`
+		Address *address `json:"address"`
+	}
+	type row struct {
+		ID    int64            `json:"id"`
+		Note  *string          `json:"note"`
+		When  time.Time        `json:"when"`
+		Took  time.Duration    `json:"took"`
+		Day   string           `json:"day"`
+		User  user             `json:"user"`
+		Tags  []string         `json:"tags"`
+		Attrs map[string]int64 `json:"attrs"`
+	}
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "note", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "when", Type: arrow.FixedWidthTypes.Timestamp_us, Nullable: true},
+		{Name: "took", Type: arrow.FixedWidthTypes.Duration_ms, Nullable: true},
+		{Name: "day", Type: arrow.FixedWidthTypes.Date32, Nullable: true},
+		{Name: "user", Type: arrow.StructOf(
+			arrow.Field{Name: "name", Type: arrow.BinaryTypes.String, Nullable: true},
+			arrow.Field{Name: "age", Type: arrow.PrimitiveTypes.Int32, Nullable: true},
+			arrow.Field{Name: "address", Type: arrow.StructOf(
+				arrow.Field{Name: "city", Type: arrow.BinaryTypes.String, Nullable: true},
+			), Nullable: true},
+		), Nullable: true},
+		{Name: "tags", Type: arrow.ListOf(arrow.BinaryTypes.String), Nullable: true},
+		{Name: "attrs", Type: arrow.MapOf(arrow.BinaryTypes.String, arrow.PrimitiveTypes.Int64), Nullable: true},
+	}, nil)
+	input := `{"id":1,"note":"n","when":"2024-01-02T03:04:05Z","took":1500,"day":"2024-01-02","user":{"name":"a","age":30,"address":{"city":"c"}},"tags":["x","y"],"attrs":{"k":1}}
+{"id":2,"note":null,"when":null,"took":null,"day":null,"user":{"name":"b","age":null,"address":null},"tags":null,"attrs":null}
+{"id":3,"user":null}
+`
+	r, err := NewReader(schema, DataSourceJSON, WithChunk(2), WithIOReader(strings.NewReader(input), '\n'))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	var got []row
+	for v, err := range DecodeInto[row](r) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, v)
+	}
+	if len(got) != 3 {
+		t.Fatalf("decoded %d rows, want 3", len(got))
+	}
+	first := got[0]
+	if first.ID != 1 || first.Note == nil || *first.Note != "n" ||
+		!first.When.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)) ||
+		first.Took != 1500*time.Millisecond || first.Day != "2024-01-02" {
+		t.Errorf("row 0 = %+v", first)
+	}
+	if u := first.User; u.Name != "a" || u.Age == nil || *u.Age != 30 || u.Address == nil || u.Address.City != "c" {
+		t.Errorf("row 0 user = %+v", u)
+	}
+	if strings.Join(first.Tags, ",") != "x,y" || len(first.Attrs) != 1 || first.Attrs["k"] != 1 {
+		t.Errorf("row 0 tags = %v, attrs = %v", first.Tags, first.Attrs)
+	}
+	// nulls leave pointers nil and other fields at their zero value
+	for i, v := range got[1:] {
+		if v.Note != nil || !v.When.IsZero() || v.Took != 0 || v.Day != "" || v.Tags != nil || v.Attrs != nil ||
+			v.User.Age != nil || v.User.Address != nil {
+			t.Errorf("row %d = %+v, want null fields zero", i+1, v)
+		}
+	}
+	if got[1].User.Name != "b" || got[2].User.Name != "" {
+		t.Errorf("user names = %q, %q, want b and none", got[1].User.Name, got[2].User.Name)
+	}
+}
+
+func TestDecodeIntoMismatch(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "tags", Type: arrow.ListOf(arrow.PrimitiveTypes.Int64), Nullable: true},
+	}, nil)
+	tests := []struct {
+		name string
+		v    func(r *DataReader) error
+		want string
+	}{
+		{"missing column", func(r *DataReader) error {
+			for _, err := range DecodeInto[struct{ Missing int }](r) {
+				return err
+			}
+			return nil
+		}, "no column for field Missing"},
+		{"element type", func(r *DataReader) error {
+			for _, err := range DecodeInto[struct {
+				ID   int64
+				Tags []string
+			}](r) {
+				return err
+			}
+			return nil
+		}, "cannot decode int64 to string"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewReader(schema, DataSourceJSON, WithIOReader(strings.NewReader(`{"id":1,"tags":[2]}`+"\n"), '\n'))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer r.Close()
+			err = tt.v(r)
+			if !errors.Is(err, ErrDecodeMismatch) || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("DecodeInto() = %v, want %s", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package reader
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+var ErrInvalidDefault = errors.New("invalid default value")
+
+// dotPath returns the path to the field in json dot notation, ie. "$a.b".
+func (f *fieldPos) dotPath() string {
+	var path []string
+	for cur := f; cur.parent != nil; cur = cur.parent {
+		path = append([]string{cur.fieldName}, path...)
+	}
+	return "$" + strings.Join(path, ".")
+}
+
+// withDefault wraps fn so that null values are appended as value.
+func withDefault(fn func(val interface{}) error, value any) func(val interface{}) error {
+	return func(data interface{}) error {
+		if data == nil {
+			data = value
+		}
+		return fn(data)
+	}
+}
+
+// checkDefaults returns an error if a default value set with WithDefault is not at the
+// path of a scalar field of schema, or can't be loaded into it.
+func checkDefaults(schema *arrow.Schema, defaults map[string]any) error {
+	for dotpath, value := range defaults {
+		field, ok := findPath(schema.Fields(), strings.Split(strings.TrimPrefix(dotpath, "$"), "."))
+		if !ok {
+			return fmt.Errorf("%w : %s : field not found", ErrInvalidDefault, dotpath)
+		}
+		if arrow.IsNested(field.Type.ID()) || field.Type.ID() == arrow.RUN_END_ENCODED {
+			return fmt.Errorf("%w : %s : %v is not a scalar type", ErrInvalidDefault, dotpath, field.Type)
+		}
+		if value == nil {
+			return fmt.Errorf("%w : %s : nil", ErrInvalidDefault, dotpath)
+		}
+		b := array.NewBuilder(memory.DefaultAllocator, field.Type)
+		scratch := newFieldPos()
+		mapFieldBuilders(b, field, scratch)
+		err := scratch.childrens[0].appendFunc(value)
+		loaded := b.Len() == 1 && b.NullN() == 0
+		b.Release()
+		if err != nil || !loaded {
+			return fmt.Errorf("%w : %s : %v (%T) can't be loaded as %v", ErrInvalidDefault, dotpath, value, value, field.Type)
+		}
+	}
+	return nil
+}
+
+// findPath returns the field found at path in fields, following struct fields.
+func findPath(fields []arrow.Field, path []string) (arrow.Field, bool) {
+	for _, f := range fields {
+		name := f.Name
+		if orig, ok := f.Metadata.GetValue(OriginalNameKey); ok {
+			name = orig
+		}
+		if name != path[0] {
+			continue
+		}
+		if len(path) == 1 {
+			return f, true
+		}
+		if st, ok := f.Type.(*arrow.StructType); ok {
+			return findPath(st.Fields(), path[1:])
+		}
+		return arrow.Field{}, false
+	}
+	return arrow.Field{}, false
+}
@@ -0,0 +1,122 @@
+package reader
+
+import (
+	"bytes"
+	stdjson "encoding/json"
+	"errors"
+	"fmt"
+)
+
+var ErrDuplicateKey = errors.New("duplicate key")
+
+// DuplicateKeyPolicy specifies how a key appearing more than once in the same JSON
+// object is handled.
+type DuplicateKeyPolicy int
+
+const (
+	// DuplicateKeyLastWins keeps the value of the last occurrence of the key, as
+	// decoding to a map does.
+	DuplicateKeyLastWins DuplicateKeyPolicy = iota
+	// DuplicateKeyFirstWins keeps the value of the first occurrence of the key.
+	DuplicateKeyFirstWins
+	// DuplicateKeyError rejects the input with an error wrapping ErrDuplicateKey.
+	DuplicateKeyError
+)
+
+// InputMapWithPolicy is InputMap, with duplicate keys in json string or []byte input
+// handled according to policy. Other inputs have no duplicate keys.
+func InputMapWithPolicy(a any, policy DuplicateKeyPolicy) (map[string]any, error) {
+	if policy == DuplicateKeyLastWins {
+		return InputMap(a)
+	}
+	switch input := a.(type) {
+	case []byte:
+		return decodeObject(input, policy)
+	case string:
+		return decodeObject([]byte(input), policy)
+	}
+	return InputMap(a)
+}
+
+// decodeObject decodes a json object token by token, so duplicate keys are seen
+// before the map overwrites them.
+func decodeObject(data []byte, policy DuplicateKeyPolicy) (map[string]any, error) {
+	d := stdjson.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	t, err := d.Token()
+	if err != nil {
+		return nil, fmt.Errorf("%v : %v", ErrInvalidInput, err)
+	}
+	if delim, ok := t.(stdjson.Delim); !ok || delim != '{' {
+		return nil, fmt.Errorf("%v : not a json object", ErrInvalidInput)
+	}
+	m, err := decodeMembers(d, policy, "$")
+	if err != nil {
+		if errors.Is(err, ErrDuplicateKey) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%v : %v", ErrInvalidInput, err)
+	}
+	return m, nil
+}
+
+// decodeMembers decodes the members of an object whose opening delimiter was read,
+// path is used in duplicate key errors.
+func decodeMembers(d *stdjson.Decoder, policy DuplicateKeyPolicy, path string) (map[string]any, error) {
+	m := map[string]any{}
+	for d.More() {
+		t, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, ok := t.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid object key %v", t)
+		}
+		v, err := decodeToken(d, policy, path+"."+key)
+		if err != nil {
+			return nil, err
+		}
+		if _, dup := m[key]; dup {
+			if policy == DuplicateKeyError {
+				return nil, fmt.Errorf("%w : %s", ErrDuplicateKey, path+"."+key)
+			}
+			continue
+		}
+		m[key] = v
+	}
+	if _, err := d.Token(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// decodeToken decodes the next json value, as json.Number for numbers.
+func decodeToken(d *stdjson.Decoder, policy DuplicateKeyPolicy, path string) (any, error) {
+	t, err := d.Token()
+	if err != nil {
+		return nil, err
+	}
+	delim, ok := t.(stdjson.Delim)
+	if !ok {
+		return t, nil
+	}
+	switch delim {
+	case '{':
+		return decodeMembers(d, policy, path)
+	case '[':
+		s := []any{}
+		for d.More() {
+			v, err := decodeToken(d, policy, path+".elem")
+			if err != nil {
+				return nil, err
+			}
+			s = append(s, v)
+		}
+		if _, err := d.Token(); err != nil {
+			return nil, err
+		}
+		return s, nil
+	}
+	return nil, fmt.Errorf("unexpected delimiter %v", delim)
+}
@@ -0,0 +1,86 @@
+package reader
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidDuration = errors.New("invalid ISO 8601 duration")
+
+// IsISODuration reports whether s is an ISO 8601 duration which ParseISODuration accepts.
+func IsISODuration(s string) bool {
+	_, err := ParseISODuration(s)
+	return err == nil
+}
+
+// ParseISODuration parses an ISO 8601 duration of the form PnW, PnDTnHnMnS or any subset
+// of its components, ie. "PT1H30M" or "P3D", with an optional sign and a fractional last
+// component. Weeks are 7 days and days are 24 hours; years and months have no fixed length
+// and are rejected.
+func ParseISODuration(s string) (time.Duration, error) {
+	str := s
+	neg := false
+	switch {
+	case strings.HasPrefix(s, "-"):
+		neg = true
+		s = s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+	if len(s) < 3 || s[0] != 'P' || strings.HasSuffix(s, "T") {
+		return 0, fmt.Errorf("%w : %q", ErrInvalidDuration, str)
+	}
+	s = s[1:]
+	units := "WD"
+	scales := map[byte]float64{
+		'W': float64(7 * 24 * time.Hour),
+		'D': float64(24 * time.Hour),
+		'H': float64(time.Hour),
+		'M': float64(time.Minute),
+		'S': float64(time.Second),
+	}
+	var total float64
+	inTime, fraction := false, false
+	for len(s) > 0 {
+		if s[0] == 'T' {
+			if inTime {
+				return 0, fmt.Errorf("%w : %q", ErrInvalidDuration, str)
+			}
+			inTime = true
+			units = "HMS"
+			s = s[1:]
+			continue
+		}
+		i := strings.IndexFunc(s, func(r rune) bool { return (r < '0' || r > '9') && r != '.' && r != ',' })
+		if i <= 0 || fraction {
+			// a component without a value, or following a fractional one
+			return 0, fmt.Errorf("%w : %q", ErrInvalidDuration, str)
+		}
+		unit := s[i]
+		j := strings.IndexByte(units, unit)
+		if j < 0 {
+			return 0, fmt.Errorf("%w : %q", ErrInvalidDuration, str)
+		}
+		// components must be in order and appear once
+		units = units[j+1:]
+		v, err := strconv.ParseFloat(strings.Replace(s[:i], ",", ".", 1), 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w : %q", ErrInvalidDuration, str)
+		}
+		fraction = strings.ContainsAny(s[:i], ".,")
+		total += v * scales[unit]
+		s = s[i+1:]
+	}
+	if total > math.MaxInt64 {
+		return 0, fmt.Errorf("%w : %q", ErrInvalidDuration, str)
+	}
+	d := time.Duration(total)
+	if neg {
+		d = -d
+	}
+	return d, nil
+}
@@ -12,28 +12,44 @@ import (
 
 const (
 	tagNameMapStructure = "mapstructure"
+	tagNameJSON         = "json"
+	tagNameBodkin       = "bodkin"
 	optionSeparator     = ","
 	optionOmitEmpty     = "omitempty"
 	optionSquash        = "squash"
 	optionRemain        = "remain"
 	optionSkip          = "-"
+	optionBodkinSkip    = "skip"
 )
 
 var (
 	errNonStringEncodedKey = errors.New("non string-encoded key")
+	ErrEncodeCycle         = errors.New("encountered a cycle")
+	ErrEncodeMaxDepth      = errors.New("max encoding depth exceeded")
 )
 
-// tagInfo stores the mapstructure tag details.
+// tagInfo stores the mapstructure, json and bodkin tag details.
 type tagInfo struct {
 	name      string
 	omitEmpty bool
 	squash    bool
+	skip      bool
 }
 
 // An Encoder takes structured data and converts it into an
 // interface following the mapstructure tags.
 type Encoder struct {
 	config *EncoderConfig
+	// pointers, maps and slices being encoded
+	visiting map[visit]struct{}
+	depth    int
+}
+
+// visit identifies a pointer, map or slice value being encoded.
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+	len int
 }
 
 // EncoderConfig is the configuration used to create a new encoder.
@@ -41,6 +57,9 @@ type EncoderConfig struct {
 	// EncodeHook, if set, is a way to provide custom encoding. It
 	// will be called before structs and primitive types.
 	EncodeHook mapstructure.DecodeHookFunc
+	// MaxDepth, if set, is the maximum nesting of pointers, maps and
+	// slices encoded, beyond which encoding fails.
+	MaxDepth int
 }
 
 // New returns a new encoder for the configuration.
@@ -50,19 +69,68 @@ func New(cfg *EncoderConfig) *Encoder {
 
 // Encode takes the input and uses reflection to encode it to
 // an interface based on the mapstructure spec.
+// Self-referential input, ie. a tree node with a pointer to its parent, returns an
+// error wrapping ErrEncodeCycle rather than recursing forever.
 func (e *Encoder) Encode(input any) (any, error) {
-	return e.encode(reflect.ValueOf(input))
+	s := &Encoder{config: e.config, visiting: make(map[visit]struct{})}
+	return s.encode(reflect.ValueOf(input))
 }
 
-// encode processes the value based on the reflect.Kind.
+// enter marks the pointer, map or slice value as being encoded, returning an error if
+// it is already being encoded by one of its ancestors or if it lies beyond the maximum
+// depth. The returned func must be called once the value is encoded.
+func (e *Encoder) enter(value reflect.Value) (func(), error) {
+	if value.IsNil() {
+		return func() {}, nil
+	}
+	v := visit{ptr: value.Pointer(), typ: value.Type()}
+	if value.Kind() == reflect.Slice {
+		v.len = value.Len()
+	}
+	if _, ok := e.visiting[v]; ok {
+		return nil, fmt.Errorf("%w via %v", ErrEncodeCycle, value.Type())
+	}
+	if e.config != nil && e.config.MaxDepth > 0 && e.depth >= e.config.MaxDepth {
+		return nil, fmt.Errorf("%w : %d", ErrEncodeMaxDepth, e.config.MaxDepth)
+	}
+	e.visiting[v] = struct{}{}
+	e.depth++
+	return func() {
+		delete(e.visiting, v)
+		e.depth--
+	}, nil
+}
+
+// encode processes the value based on the reflect.Kind. time.Time and time.Duration
+// values are returned as is, to be inferred and loaded as timestamps and durations.
 func (e *Encoder) encode(value reflect.Value) (any, error) {
 	if value.IsValid() {
+		if t := value.Type(); t == timeType || t == durationType {
+			return value.Interface(), nil
+		}
 		switch value.Kind() {
-		case reflect.Interface, reflect.Ptr:
+		case reflect.Interface:
+			return e.encode(value.Elem())
+		case reflect.Ptr:
+			leave, err := e.enter(value)
+			if err != nil {
+				return nil, err
+			}
+			defer leave()
 			return e.encode(value.Elem())
 		case reflect.Map:
+			leave, err := e.enter(value)
+			if err != nil {
+				return nil, err
+			}
+			defer leave()
 			return e.encodeMap(value)
 		case reflect.Slice:
+			leave, err := e.enter(value)
+			if err != nil {
+				return nil, err
+			}
+			defer leave()
 			return e.encodeSlice(value)
 		case reflect.Struct:
 			return e.encodeStruct(value)
@@ -110,7 +178,7 @@ func (e *Encoder) encodeStruct(value reflect.Value) (any, error) {
 		field := value.Field(i)
 		if field.CanInterface() {
 			info := getTagInfo(value.Type().Field(i))
-			if (info.omitEmpty && field.IsZero()) || info.name == optionSkip {
+			if info.skip || (info.omitEmpty && isEmptyValue(field)) {
 				continue
 			}
 			encoded, err := e.encode(field)
@@ -149,8 +217,9 @@ func (e *Encoder) encodeSlice(value reflect.Value) (any, error) {
 	return result, nil
 }
 
-// encodeMap encodes a map by encoding the key and value. Returns errNonStringEncodedKey
-// if the key is not encoded into a string.
+// encodeMap encodes a map by encoding the key and value. Maps whose keys are all
+// encoded into strings are encoded as map[string]any, others as map[any]any, which
+// Bodkin infers as an Arrow map.
 func (e *Encoder) encodeMap(value reflect.Value) (any, error) {
 	if value.Kind() != reflect.Map {
 		return nil, &reflect.ValueError{
@@ -158,41 +227,67 @@ func (e *Encoder) encodeMap(value reflect.Value) (any, error) {
 			Kind:   value.Kind(),
 		}
 	}
-	result := make(map[string]any)
+	keys := make([]any, 0, value.Len())
+	values := make([]any, 0, value.Len())
+	stringKeys := true
 	iterator := value.MapRange()
 	for iterator.Next() {
-		encoded, err := e.encode(iterator.Key())
+		key, err := e.encode(iterator.Key())
 		if err != nil {
 			return nil, fmt.Errorf("error encoding key: %w", err)
 		}
-
-		v := reflect.ValueOf(encoded)
-		var key string
-
-		switch v.Kind() {
-		case reflect.String:
-			key = v.String()
-		default:
-			return nil, fmt.Errorf("%w, key: %q, kind: %v, type: %T", errNonStringEncodedKey, iterator.Key().Interface(), iterator.Key().Kind(), encoded)
+		if _, ok := key.(string); !ok {
+			stringKeys = false
 		}
-
+		v, err := e.encode(iterator.Value())
+		if err != nil {
+			return nil, fmt.Errorf("error encoding map value for key %v: %w", key, err)
+		}
+		keys = append(keys, key)
+		values = append(values, v)
+	}
+	if !stringKeys {
+		result := make(map[any]any, len(keys))
+		for i, k := range keys {
+			if k == nil || !reflect.TypeOf(k).Comparable() {
+				return nil, fmt.Errorf("%w, key: %v, type: %T", errNonStringEncodedKey, k, k)
+			}
+			if _, ok := result[k]; ok {
+				return nil, fmt.Errorf("duplicate key %v while encoding", k)
+			}
+			result[k] = values[i]
+		}
+		return result, nil
+	}
+	result := make(map[string]any, len(keys))
+	for i, k := range keys {
+		key := k.(string)
 		if _, ok := result[key]; ok {
 			return nil, fmt.Errorf("duplicate key %q while encoding", key)
 		}
-		if result[key], err = e.encode(iterator.Value()); err != nil {
-			return nil, fmt.Errorf("error encoding map value for key %q: %w", key, err)
-		}
+		result[key] = values[i]
 	}
 	return result, nil
 }
 
-// getTagInfo looks up the mapstructure tag and uses that if available.
-// Uses the lowercase field if not found. Checks for omitempty and squash.
+// getTagInfo looks up the field's tags to determine its name and options.
+// A `bodkin:"skip"` tag omits the field. Otherwise the mapstructure tag is used if
+// available, then the json tag following encoding/json conventions, ie. `json:"-"`
+// omits the field and `json:",omitempty"` keeps the field name as is.
+// Uses the lowercase field name if there is no mapstructure or json name.
+// Checks for omitempty and squash.
 func getTagInfo(field reflect.StructField) *tagInfo {
 	info := tagInfo{}
+	if tag, ok := field.Tag.Lookup(tagNameBodkin); ok && tag == optionBodkinSkip {
+		info.skip = true
+		return &info
+	}
 	if tag, ok := field.Tag.Lookup(tagNameMapStructure); ok {
 		options := strings.Split(tag, optionSeparator)
 		info.name = options[0]
+		if info.name == optionSkip {
+			info.skip = true
+		}
 		if len(options) > 1 {
 			for _, option := range options[1:] {
 				switch option {
@@ -203,12 +298,47 @@ func getTagInfo(field reflect.StructField) *tagInfo {
 				}
 			}
 		}
-	} else {
+	} else if tag, ok := field.Tag.Lookup(tagNameJSON); ok {
+		if tag == optionSkip {
+			info.skip = true
+			return &info
+		}
+		options := strings.Split(tag, optionSeparator)
+		info.name = options[0]
+		for _, option := range options[1:] {
+			if option == optionOmitEmpty {
+				info.omitEmpty = true
+			}
+		}
+		// embedded structs without a json name are flattened by encoding/json,
+		// other fields keep their name as is
+		if info.name == "" {
+			info.squash = field.Anonymous
+			info.name = field.Name
+		}
+	}
+	if info.name == "" {
 		info.name = strings.ToLower(field.Name)
 	}
 	return &info
 }
 
+// isEmptyValue reports whether v is empty as defined by encoding/json's omitempty,
+// ie. false, 0, a nil pointer or interface, and any empty array, slice, map, or string.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	}
+	return false
+}
+
 // TextMarshalerHookFunc returns a DecodeHookFuncValue that checks
 // for the encoding.TextMarshaler interface and calls the MarshalText
 // function if found.
@@ -0,0 +1,35 @@
+package reader
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetTagInfoName(t *testing.T) {
+	type embedded struct{}
+	type row struct {
+		Plain     int
+		Tagged    int `json:"tagged_name"`
+		OmitEmpty int `json:",omitempty"`
+		Mapped    int `mapstructure:"mapped_name"`
+		Untagged  int `mapstructure:",omitempty"`
+		Skipped   int `json:"-"`
+		embedded  `json:""`
+	}
+	tests := map[string]tagInfo{
+		"Plain":     {name: "plain"},
+		"Tagged":    {name: "tagged_name"},
+		"OmitEmpty": {name: "OmitEmpty", omitEmpty: true},
+		"Mapped":    {name: "mapped_name"},
+		"Untagged":  {name: "untagged", omitEmpty: true},
+		"Skipped":   {skip: true},
+		"embedded":  {name: "embedded", squash: true},
+	}
+	rt := reflect.TypeFor[row]()
+	for field, want := range tests {
+		sf, _ := rt.FieldByName(field)
+		if got := getTagInfo(sf); *got != want {
+			t.Errorf("getTagInfo(%s) = %+v, want %+v", field, *got, want)
+		}
+	}
+}
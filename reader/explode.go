@@ -0,0 +1,139 @@
+package reader
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+var ErrExplode = errors.New("cannot explode")
+
+// Explode returns a new reader loading one row per element of the top-level list of
+// structs at arrayDotpath, ie. "$results", with the other top-level columns repeated
+// on each row, like a SQL LATERAL unnest. The fields of the element struct become
+// top-level columns following the other columns of r's schema; an error is returned
+// if one of their names is already taken.
+// A datum whose list is null or empty loads a single row with null element columns.
+//
+// Only one list is exploded per call. To explode a list nested in the elements, or a
+// second top-level list, call Explode on the returned reader: rows are then the product
+// of the lists' elements.
+//
+// The returned reader has r's options, but it does not read r's io.Reader: its input
+// is passed with Read or ReadToRecord. The JSON decoder of WithJSONDecoder is not
+// supported.
+func (r *DataReader) Explode(arrayDotpath string) (*DataReader, error) {
+	if r.jsonDecode {
+		return nil, fmt.Errorf("%w : not supported with the JSON decoder", ErrExplode)
+	}
+	key := strings.TrimPrefix(strings.TrimPrefix(arrayDotpath, "$"), ".")
+	if key == "" || strings.Contains(key, ".") {
+		return nil, fmt.Errorf("%w %s : only top-level lists can be exploded", ErrExplode, arrayDotpath)
+	}
+	schema := r.schema
+	if r.contentHash != nil {
+		// the hash column is added again by the new reader
+		hash := r.contentHash.field().Name
+		md := schema.Metadata()
+		schema = arrow.NewSchema(slices.DeleteFunc(schema.Fields(), func(f arrow.Field) bool { return f.Name == hash }), &md)
+	}
+	exploded, err := explodedSchema(schema, key)
+	if err != nil {
+		return nil, err
+	}
+	opts := append(slices.Clone(r.opts), withoutInput(), withExplode(key))
+	return NewReader(exploded, r.source, opts...)
+}
+
+// withoutInput removes the io.Reader set by the preceding options.
+func withoutInput() Option {
+	return func(cfg config) {
+		cfg.rr, cfg.br = nil, nil
+	}
+}
+
+// withExplode adds top-level input key to the lists exploded by the reader.
+func withExplode(key string) Option {
+	return func(cfg config) {
+		cfg.explode = append(slices.Clone(cfg.explode), key)
+	}
+}
+
+// explodedSchema returns schema with its top-level list of structs field with input name
+// key replaced by the fields of its element struct.
+func explodedSchema(schema *arrow.Schema, key string) (*arrow.Schema, error) {
+	var fields, elemFields []arrow.Field
+	found := false
+	for _, f := range schema.Fields() {
+		name := f.Name
+		if orig, ok := f.Metadata.GetValue(OriginalNameKey); ok {
+			name = orig
+		}
+		if name != key {
+			fields = append(fields, f)
+			continue
+		}
+		found = true
+		lt, ok := f.Type.(arrow.ListLikeType)
+		if !ok || f.Type.ID() == arrow.MAP {
+			return nil, fmt.Errorf("%w $%s : %v is not a list", ErrExplode, key, f.Type)
+		}
+		st, ok := lt.Elem().(*arrow.StructType)
+		if !ok {
+			return nil, fmt.Errorf("%w $%s : %v is not a list of structs", ErrExplode, key, f.Type)
+		}
+		elemFields = st.Fields()
+	}
+	if !found {
+		return nil, fmt.Errorf("%w $%s : %w", ErrExplode, key, ErrProjectionNotFound)
+	}
+	for _, f := range elemFields {
+		if hasField(fields, f.Name) {
+			return nil, fmt.Errorf("%w $%s : element field %s is already a column", ErrExplode, key, f.Name)
+		}
+		fields = append(fields, f)
+	}
+	md := schema.Metadata()
+	return arrow.NewSchema(fields, &md), nil
+}
+
+// explodeRows returns the rows of datum data, one per element of each exploded list.
+func (r *DataReader) explodeRows(data any) []any {
+	rows := []any{data}
+	for _, key := range r.explode {
+		var next []any
+		for _, row := range rows {
+			next = append(next, explodeRow(row, key)...)
+		}
+		rows = next
+	}
+	return rows
+}
+
+// explodeRow returns a row per element of the list at key of row, holding the other
+// keys of row and the keys of the element.
+func explodeRow(row any, key string) []any {
+	m, ok := row.(map[string]any)
+	if !ok {
+		return []any{row}
+	}
+	base := maps.Clone(m)
+	delete(base, key)
+	elems, _ := m[key].([]any)
+	if len(elems) == 0 {
+		return []any{base}
+	}
+	rows := make([]any, 0, len(elems))
+	for _, e := range elems {
+		out := maps.Clone(base)
+		if em, ok := e.(map[string]any); ok {
+			maps.Copy(out, em)
+		}
+		rows = append(rows, out)
+	}
+	return rows
+}
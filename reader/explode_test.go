@@ -0,0 +1,80 @@
+package reader
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+func TestExplode(t *testing.T) {
+	elem := arrow.StructOf(
+		arrow.Field{Name: "n", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+		arrow.Field{Name: "s", Type: arrow.BinaryTypes.String, Nullable: true},
+	)
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+		{Name: "results", Type: arrow.ListOf(elem), Nullable: true},
+		{Name: "tags", Type: arrow.ListOf(arrow.BinaryTypes.String), Nullable: true},
+	}, nil)
+	r, err := NewReader(schema, DataSourceJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Release()
+	er, err := r.Explode("$results")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer er.Release()
+	want := []string{"id", "tags", "n", "s"}
+	var names []string
+	for _, f := range er.Schema().Fields() {
+		names = append(names, f.Name)
+	}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Fatalf("exploded schema = %v, want %v", names, want)
+	}
+	for _, tt := range []struct {
+		in   string
+		rows []string
+	}{
+		{`{"id":1,"results":[{"n":1,"s":"a"},{"n":2,"s":"b"}],"tags":["x"]}`, []string{"1 1 a", "1 2 b"}},
+		{`{"id":2,"results":[],"tags":["y"]}`, []string{"2 (null) (null)"}},
+		{`{"id":3,"results":null}`, []string{"3 (null) (null)"}},
+	} {
+		rec, err := er.ReadToRecord([]byte(tt.in))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var rows []string
+		for i := 0; i < int(rec.NumRows()); i++ {
+			rows = append(rows, rec.Column(0).ValueStr(i)+" "+rec.Column(2).ValueStr(i)+" "+rec.Column(3).ValueStr(i))
+		}
+		rec.Release()
+		if strings.Join(rows, "|") != strings.Join(tt.rows, "|") {
+			t.Errorf("%s rows = %q, want %q", tt.in, rows, tt.rows)
+		}
+	}
+}
+
+func TestExplodeErrors(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "n", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+		{Name: "tags", Type: arrow.ListOf(arrow.BinaryTypes.String), Nullable: true},
+		{Name: "results", Type: arrow.ListOf(arrow.StructOf(
+			arrow.Field{Name: "n", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+		)), Nullable: true},
+	}, nil)
+	r, err := NewReader(schema, DataSourceJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Release()
+	for _, path := range []string{"$n", "$tags", "$results", "$results.n", "$missing"} {
+		if _, err := r.Explode(path); !errors.Is(err, ErrExplode) {
+			t.Errorf("Explode(%s) = %v, want ErrExplode", path, err)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package reader
+
+import (
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+// ExtraColumnKey is the field metadata key marking a top-level map column which holds
+// the top-level keys of the input that are not fields of the schema.
+const ExtraColumnKey = "bodkin.extra"
+
+// extraColumn returns the input name of the extra column of schema, and the input names
+// of its other top-level fields.
+func extraColumn(schema *arrow.Schema) (string, map[string]bool) {
+	var extra string
+	names := make(map[string]bool)
+	for _, f := range schema.Fields() {
+		name := f.Name
+		if orig, ok := f.Metadata.GetValue(OriginalNameKey); ok {
+			name = orig
+		}
+		if _, ok := f.Metadata.GetValue(ExtraColumnKey); ok && f.Type.ID() == arrow.MAP {
+			extra = name
+			continue
+		}
+		names[name] = true
+	}
+	if extra == "" {
+		return "", nil
+	}
+	return extra, names
+}
+
+// withExtra returns datum data with its top-level keys which are not fields of the
+// schema moved into the extra column's map. A key of the input named as the extra
+// column is moved into the map too, rather than being overwritten by it.
+func (r *DataReader) withExtra(data any) any {
+	m, ok := data.(map[string]any)
+	if !ok {
+		return data
+	}
+	var extra map[string]any
+	for k := range m {
+		if !r.fieldNames[k] {
+			if extra == nil {
+				extra = make(map[string]any)
+			}
+			extra[k] = m[k]
+		}
+	}
+	if extra == nil {
+		return data
+	}
+	out := make(map[string]any, len(m)-len(extra)+1)
+	for k, v := range m {
+		if _, ok := extra[k]; !ok {
+			out[k] = v
+		}
+	}
+	out[r.extra] = extra
+	return out
+}
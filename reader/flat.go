@@ -0,0 +1,43 @@
+package reader
+
+import (
+	"errors"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+// flatLoader loads datum to a schema without nested fields, looking up each field's
+// value by its input key rather than walking its path as dataLoader does.
+type flatLoader struct {
+	keys   []string
+	fields []*fieldPos
+}
+
+// newFlatLoader returns a flatLoader for the top-level fields of root, the field builders
+// of schema, or nil if schema has nested fields.
+func newFlatLoader(schema *arrow.Schema, root *fieldPos) *flatLoader {
+	for _, f := range schema.Fields() {
+		if arrow.IsNested(f.Type.ID()) || f.Type.ID() == arrow.RUN_END_ENCODED || f.Type.ID() == arrow.EXTENSION {
+			return nil
+		}
+	}
+	l := &flatLoader{}
+	for _, f := range root.children() {
+		l.keys = append(l.keys, f.fieldName)
+		l.fields = append(l.fields, f)
+	}
+	return l
+}
+
+// loadDatum appends the value of each field in m, or null if it is absent. A field
+// which fails to load has appended null, so the remaining fields are loaded and the
+// errors returned together.
+func (l *flatLoader) loadDatum(m map[string]any) error {
+	var errs error
+	for i, f := range l.fields {
+		if err := f.appendFunc(m[l.keys[i]]); err != nil {
+			errs = errors.Join(errs, err)
+		}
+	}
+	return errs
+}
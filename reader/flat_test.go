@@ -0,0 +1,64 @@
+package reader
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+// wideFlatInput returns a schema of n scalar fields and a datum with a value for each.
+func wideFlatInput(n int) (*arrow.Schema, map[string]any) {
+	fields := make([]arrow.Field, n)
+	m := make(map[string]any, n)
+	for i := range fields {
+		name := fmt.Sprintf("f%d", i)
+		switch i % 4 {
+		case 0:
+			fields[i] = arrow.Field{Name: name, Type: arrow.PrimitiveTypes.Int64, Nullable: true}
+			m[name] = int64(i)
+		case 1:
+			fields[i] = arrow.Field{Name: name, Type: arrow.PrimitiveTypes.Float64, Nullable: true}
+			m[name] = float64(i) + 0.5
+		case 2:
+			fields[i] = arrow.Field{Name: name, Type: arrow.BinaryTypes.String, Nullable: true}
+			m[name] = name
+		case 3:
+			fields[i] = arrow.Field{Name: name, Type: arrow.FixedWidthTypes.Boolean, Nullable: true}
+			m[name] = i%2 == 0
+		}
+	}
+	return arrow.NewSchema(fields, nil), m
+}
+
+func BenchmarkLoadFlat(b *testing.B) {
+	schema, m := wideFlatInput(64)
+	for _, bc := range []struct {
+		name string
+		load func(r *DataReader) error
+	}{
+		{"flatLoader", func(r *DataReader) error { return r.flat.loadDatum(m) }},
+		{"dataLoader", func(r *DataReader) error { return r.ldr.loadDatum(m) }},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			r, err := NewReader(schema, DataSourceGo)
+			if err != nil {
+				b.Fatal(err)
+			}
+			defer r.Release()
+			if r.flat == nil {
+				b.Fatal("no flatLoader for scalar schema")
+			}
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := bc.load(r); err != nil {
+					b.Fatal(err)
+				}
+				if i%1024 == 1023 {
+					r.bld.NewRecord().Release()
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package reader
+
+import (
+	"crypto/sha256"
+	stdjson "encoding/json"
+	"maps"
+	"strings"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+// contentHash computes the content hash column added by WithContentHash.
+type contentHash struct {
+	column string
+	paths  [][]string
+}
+
+func newContentHash(column string, fields []string) *contentHash {
+	h := &contentHash{column: column}
+	for _, f := range fields {
+		h.paths = append(h.paths, strings.Split(strings.TrimPrefix(strings.TrimPrefix(f, "$"), "."), "."))
+	}
+	return h
+}
+
+// field returns the Arrow field of the content hash column.
+func (h *contentHash) field() arrow.Field {
+	return arrow.Field{Name: h.column, Type: &arrow.FixedSizeBinaryType{ByteWidth: sha256.Size}, Nullable: true}
+}
+
+// sum returns the SHA-256 digest of the canonical JSON encoding of the datum's
+// hashed fields, or of the whole datum if no fields were specified.
+func (h *contentHash) sum(m map[string]any) []byte {
+	var v any = m
+	if len(h.paths) > 0 {
+		values := make([]any, len(h.paths))
+		for i, p := range h.paths {
+			values[i] = valueAtPath(m, p)
+		}
+		v = values
+	}
+	// encoding/json sorts map keys, and json.Number values keep their input text.
+	b, err := stdjson.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	sum := sha256.Sum256(b)
+	return sum[:]
+}
+
+// withHash returns a shallow copy of the datum with its content hash added.
+func (h *contentHash) withHash(data any) any {
+	m, ok := data.(map[string]any)
+	if !ok {
+		return data
+	}
+	m = maps.Clone(m)
+	// a datum that cannot be encoded gets a null hash
+	if sum := h.sum(m); sum != nil {
+		m[h.column] = sum
+	}
+	return m
+}
+
+// valueAtPath returns the value found by following path's keys in m, or nil.
+func valueAtPath(m map[string]any, path []string) any {
+	var value any = m
+	for _, key := range path {
+		valueMap, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value, ok = valueMap[key]
+		if !ok {
+			return nil
+		}
+	}
+	return value
+}
@@ -2,8 +2,10 @@ package reader
 
 import (
 	"bytes"
+	stdjson "encoding/json"
 	"errors"
 	"fmt"
+	"reflect"
 
 	"github.com/go-viper/mapstructure/v2"
 	json "github.com/goccy/go-json"
@@ -17,6 +19,10 @@ var (
 // InputMap takes structured input data and attempts to decode it to
 // map[string]any. Input data can be json in string or []byte, or any other
 // Go data type which can be decoded by [MapStructure/v2].
+// Go struct fields are named using their mapstructure or json tags, fields tagged
+// `bodkin:"skip"` or `json:"-"` are omitted. time.Time and time.Duration fields are
+// kept as is, to be inferred and loaded as timestamps and durations. Self-referential
+// Go data, ie. entities with back-references, returns an error wrapping ErrEncodeCycle.
 // [MapStructure/v2]: github.com/go-viper/mapstructure/v2
 func InputMap(a any) (map[string]any, error) {
 	m := map[string]any{}
@@ -47,7 +53,108 @@ func InputMap(a any) (map[string]any, error) {
 		if err != nil {
 			return nil, fmt.Errorf("Error decoding to map[string]interface{}: %v", err)
 		}
-		return enc.(map[string]any), nil
+		m, ok := enc.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%v : %T does not encode to map[string]interface{}", ErrInvalidInput, a)
+		}
+		return m, nil
 	}
 	return m, nil
 }
+
+// DefaultValueField is the field name under which top-level values which are not JSON
+// objects are wrapped if no other name is set.
+const DefaultValueField = "value"
+
+// WrapTopLevelValue returns raw JSON input a, a []byte or string, wrapped in an object
+// under key name if it is not a JSON object, ie. "a" becomes {"value":"a"} and [1,2]
+// becomes {"value":[1,2]}, so that streams of scalars or arrays can be inferred and
+// loaded. Objects, blank input and other input types are returned as is.
+func WrapTopLevelValue(a any, name string) any {
+	var raw []byte
+	switch t := a.(type) {
+	case []byte:
+		raw = t
+	case string:
+		raw = []byte(t)
+	default:
+		return a
+	}
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || raw[0] == '{' {
+		return a
+	}
+	key, _ := json.Marshal(name)
+	wrapped := make([]byte, 0, len(key)+len(raw)+3)
+	wrapped = append(wrapped, '{')
+	wrapped = append(wrapped, key...)
+	wrapped = append(wrapped, ':')
+	wrapped = append(wrapped, raw...)
+	wrapped = append(wrapped, '}')
+	if _, ok := a.(string); ok {
+		return string(wrapped)
+	}
+	return wrapped
+}
+
+// InputKeys returns the top-level keys of structured input data in the order in
+// which they appear. Input data can be json in string or []byte, or a Go struct.
+// A nil slice is returned for input types without an inherent key order, ie. map[string]any.
+func InputKeys(a any) ([]string, error) {
+	switch input := a.(type) {
+	case nil:
+		return nil, ErrUndefinedInput
+	case map[string]any:
+		return nil, nil
+	case []byte:
+		return jsonKeys(input)
+	case string:
+		return jsonKeys([]byte(input))
+	default:
+		v := reflect.Indirect(reflect.ValueOf(a))
+		if v.Kind() != reflect.Struct {
+			return nil, nil
+		}
+		var keys []string
+		for i := 0; i < v.NumField(); i++ {
+			if !v.Field(i).CanInterface() {
+				continue
+			}
+			info := getTagInfo(v.Type().Field(i))
+			if info.skip || info.squash {
+				continue
+			}
+			keys = append(keys, info.name)
+		}
+		return keys, nil
+	}
+}
+
+// jsonKeys returns the keys of a json object in the order in which they appear.
+func jsonKeys(data []byte) ([]string, error) {
+	d := stdjson.NewDecoder(bytes.NewReader(data))
+	t, err := d.Token()
+	if err != nil {
+		return nil, fmt.Errorf("%v : %v", ErrInvalidInput, err)
+	}
+	if delim, ok := t.(stdjson.Delim); !ok || delim != '{' {
+		return nil, fmt.Errorf("%v : not a json object", ErrInvalidInput)
+	}
+	var keys []string
+	for d.More() {
+		t, err := d.Token()
+		if err != nil {
+			return nil, fmt.Errorf("%v : %v", ErrInvalidInput, err)
+		}
+		key, ok := t.(string)
+		if !ok {
+			return nil, fmt.Errorf("%v : invalid object key %v", ErrInvalidInput, t)
+		}
+		keys = append(keys, key)
+		var skip stdjson.RawMessage
+		if err := d.Decode(&skip); err != nil {
+			return nil, fmt.Errorf("%v : %v", ErrInvalidInput, err)
+		}
+	}
+	return keys, nil
+}
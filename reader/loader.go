@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"reflect"
 	"strconv"
 	"time"
 
@@ -30,9 +31,15 @@ type dataLoader struct {
 }
 
 var (
-	ErrNullStructData = errors.New("null struct data")
+	ErrNullStructData     = errors.New("null struct data")
+	ErrInvalidIntegerData = errors.New("invalid integer data")
+	ErrInvalidDate64Data  = errors.New("invalid date64 data")
 )
 
+// OriginalNameKey is the field metadata key holding the input data's name for
+// a field whose Arrow name differs from it, ie. after name sanitization.
+const OriginalNameKey = "bodkin.original_name"
+
 func newDataLoader() *dataLoader { return &dataLoader{idx: 0, depth: 0} }
 
 // drawTree takes the tree of field builders produced by mapFieldBuilders()
@@ -77,141 +84,72 @@ func (d *dataLoader) drawTree(field *fieldPos) {
 // struct's fields, in the case of nil being passed to a struct's builderFunc it will
 // return a ErrNullStructData error to signal that all its sub-fields can be skipped.
 func (d *dataLoader) loadDatum(data any) error {
+	var errs error
 	if d.list == nil && d.mapField == nil {
 		if d.mapValue != nil {
-			d.mapValue.appendFunc(data)
-		}
-		var NullParent *fieldPos
-		for _, f := range d.fields {
-			if f.parent == NullParent {
-				continue
+			switch err := d.mapValue.appendFunc(data); {
+			case err == ErrNullStructData:
+				// the null struct appended null to its fields
+				return nil
+			case err != nil:
+				errs = errors.Join(errs, err)
 			}
-			if d.mapValue == nil {
-				err := f.appendFunc(f.getValue(data))
-				if err != nil {
-					if err == ErrNullStructData {
-						NullParent = f
-						continue
-					}
-					return err
-				}
-			} else {
-				switch dt := data.(type) {
-				case nil:
-					err := f.appendFunc(dt)
-					if err != nil {
-						if err == ErrNullStructData {
-							NullParent = f
-							continue
-						}
-						return err
-					}
-				case []any:
-					if len(d.children) < 1 {
-						for _, e := range dt {
-							err := f.appendFunc(e)
-							if err != nil {
-								if err == ErrNullStructData {
-									NullParent = f
-									continue
-								}
-								return err
+		}
+		if d.mapValue == nil {
+			errs = errors.Join(errs, loadFields(d.fields, data))
+		} else {
+			switch dt := data.(type) {
+			case nil:
+				errs = errors.Join(errs, loadFields(d.fields, dt))
+			case []any:
+				if len(d.children) < 1 {
+					for _, e := range dt {
+						for _, f := range d.fields {
+							if err := f.appendFunc(e); err != nil && err != ErrNullStructData {
+								errs = errors.Join(errs, err)
 							}
 						}
-					} else {
-						for _, e := range dt {
-							d.children[0].loadDatum(e)
-						}
 					}
-				case map[string]any:
-					err := f.appendFunc(f.getValue(dt))
-					if err != nil {
-						if err == ErrNullStructData {
-							NullParent = f
-							continue
-						}
-						return err
+				} else {
+					for _, e := range dt {
+						errs = errors.Join(errs, d.children[0].loadDatum(e))
 					}
 				}
-
+			case map[string]any:
+				errs = errors.Join(errs, loadFields(d.fields, dt))
 			}
 		}
 		for _, c := range d.children {
 			if c.list != nil {
-				c.loadDatum(c.list.getValue(data))
+				errs = errors.Join(errs, c.loadDatum(c.list.getValue(data)))
 			}
 			if c.mapField != nil {
-				switch dt := data.(type) {
-				case nil:
-					c.loadDatum(dt)
-				case map[string]any:
-					c.loadDatum(c.mapField.getValue(dt))
-				default:
-					c.loadDatum(c.mapField.getValue(data))
-				}
+				errs = errors.Join(errs, c.loadDatum(c.mapField.getValue(data)))
 			}
 		}
 	} else {
 		if d.list != nil {
+			if d.list.fixedLen > 0 && !hasLen(data, d.list.fixedLen) {
+				// a fixed size list of the wrong length is loaded as null
+				data = nil
+			}
 			switch dt := data.(type) {
 			case nil:
 				d.list.appendFunc(dt)
 			case []any:
+				dt = d.list.truncate(dt)
 				d.list.appendFunc(dt)
 				for _, e := range dt {
-					if d.item != nil {
-						d.item.appendFunc(e)
-					}
-					var NullParent *fieldPos
-					for _, f := range d.fields {
-						if f.parent == NullParent {
-							continue
-						}
-						err := f.appendFunc(f.getValue(e))
-						if err != nil {
-							if err == ErrNullStructData {
-								NullParent = f
-								continue
-							}
-							return err
-						}
-					}
-					for _, c := range d.children {
-						if c.list != nil {
-							c.loadDatum(c.list.getValue(e))
-						}
-						if c.mapField != nil {
-							c.loadDatum(c.mapField.getValue(e))
-						}
-					}
+					errs = errors.Join(errs, d.loadElement(e))
 				}
 			case map[string]any:
-				d.list.appendFunc(dt)  //
-				for _, e := range dt { //
-					if d.item != nil {
-						d.item.appendFunc(e)
-					}
-					var NullParent *fieldPos
-					for _, f := range d.fields {
-						if f.parent == NullParent {
-							continue
-						}
-						err := f.appendFunc(f.getValue(e))
-						if err != nil {
-							if err == ErrNullStructData {
-								NullParent = f
-								continue
-							}
-							return err
-						}
-					}
-					for _, c := range d.children {
-						c.loadDatum(c.list.getValue(e))
-					}
+				d.list.appendFunc(dt)
+				for _, e := range dt {
+					errs = errors.Join(errs, d.loadElement(e))
 				}
 			default:
 				d.list.appendFunc(data)
-				d.item.appendFunc(dt)
+				errs = errors.Join(errs, d.item.appendFunc(dt))
 			}
 		}
 		if d.mapField != nil {
@@ -221,17 +159,86 @@ func (d *dataLoader) loadDatum(data any) error {
 			case map[string]any:
 				d.mapField.appendFunc(dt)
 				for k, v := range dt {
-					d.mapKey.appendFunc(k)
-					if d.mapValue != nil {
-						d.mapValue.appendFunc(v)
-					} else {
-						d.children[0].loadDatum(v)
-					}
+					errs = errors.Join(errs, d.loadEntry(k, v))
+				}
+			case map[any]any:
+				// Go maps with non-string keys
+				d.mapField.appendFunc(dt)
+				for k, v := range dt {
+					errs = errors.Join(errs, d.loadEntry(k, v))
 				}
 			}
 		}
 	}
-	return nil
+	return errs
+}
+
+// loadElement loads list element e to the list's item and its fields.
+func (d *dataLoader) loadElement(e any) error {
+	var errs error
+	if d.item != nil {
+		switch err := d.item.appendFunc(e); {
+		case err == ErrNullStructData:
+			// the null struct appended null to its fields
+			return nil
+		case err != nil:
+			errs = errors.Join(errs, err)
+		}
+	}
+	errs = errors.Join(errs, loadFields(d.fields, e))
+	for _, c := range d.children {
+		if c.list != nil {
+			errs = errors.Join(errs, c.loadDatum(c.list.getValue(e)))
+		}
+		if c.mapField != nil {
+			errs = errors.Join(errs, c.loadDatum(c.mapField.getValue(e)))
+		}
+	}
+	return errs
+}
+
+// loadEntry loads the key and value of a map entry.
+func (d *dataLoader) loadEntry(k, v any) error {
+	err := d.mapKey.appendFunc(k)
+	if d.mapValue != nil {
+		return errors.Join(err, d.mapValue.appendFunc(v))
+	}
+	return errors.Join(err, d.children[0].loadDatum(v))
+}
+
+// loadFields appends the values of fields found in data. The descendants of a null
+// struct are skipped, as the struct appended null to them. A field which fails to load
+// has appended null, so the remaining fields are loaded and the errors returned
+// together, leaving the row complete.
+func loadFields(fields []*fieldPos, data any) error {
+	var errs error
+	var nullParent *fieldPos
+	for _, f := range fields {
+		if nullParent != nil && f.descends(nullParent) {
+			continue
+		}
+		switch err := f.appendFunc(f.getValue(data)); {
+		case err == ErrNullStructData:
+			nullParent = f
+		case err != nil:
+			errs = errors.Join(errs, err)
+		}
+	}
+	return errs
+}
+
+// sameRun reports whether scalar value data continues the run of value last.
+func sameRun(last, data any) bool {
+	if data == nil {
+		return last == nil
+	}
+	return reflect.TypeOf(data).Comparable() && last == data
+}
+
+// hasLen reports whether data is an array of n elements.
+func hasLen(data any, n int32) bool {
+	s, ok := data.([]any)
+	return ok && len(s) == int(n)
 }
 
 func (d *dataLoader) newChild() *dataLoader {
@@ -274,6 +281,16 @@ type fieldPos struct {
 	typeName     string
 	appendFunc   func(val interface{}) error
 	metadatas    arrow.Metadata
+	boolTokens   map[string]bool
+	timeLayouts  []string
+	location     *time.Location
+	arrayLimit   *arrayLimit
+	avroTypes    *AvroLogicalTypeMap
+	utf8Policy   InvalidUTF8Policy
+	blankAsNull  bool
+	defaults     map[string]any
+	fixedLen     int32
+	dictValues   *array.String
 	childrens    []*fieldPos
 	index, depth int32
 }
@@ -282,15 +299,33 @@ func newFieldPos() *fieldPos { return &fieldPos{index: -1} }
 
 func (f *fieldPos) children() []*fieldPos { return f.childrens }
 
+// descends reports whether f is a descendant of p.
+func (f *fieldPos) descends(p *fieldPos) bool {
+	for cur := f.parent; cur != nil; cur = cur.parent {
+		if cur == p {
+			return true
+		}
+	}
+	return false
+}
+
 func (f *fieldPos) newChild(childName string, childBuilder array.Builder, meta arrow.Metadata) *fieldPos {
 	var child fieldPos = fieldPos{
-		parent:    f,
-		source:    f.source,
-		fieldName: childName,
-		builder:   childBuilder,
-		metadatas: meta,
-		index:     int32(len(f.childrens)),
-		depth:     f.depth + 1,
+		parent:      f,
+		source:      f.source,
+		boolTokens:  f.boolTokens,
+		timeLayouts: f.timeLayouts,
+		location:    f.location,
+		arrayLimit:  f.arrayLimit,
+		avroTypes:   f.avroTypes,
+		utf8Policy:  f.utf8Policy,
+		blankAsNull: f.blankAsNull,
+		defaults:    f.defaults,
+		fieldName:   childName,
+		builder:     childBuilder,
+		metadatas:   meta,
+		index:       int32(len(f.childrens)),
+		depth:       f.depth + 1,
 	}
 	if f.isList {
 		child.isItem = true
@@ -300,6 +335,20 @@ func (f *fieldPos) newChild(childName string, childBuilder array.Builder, meta a
 	return &child
 }
 
+// resetDictionaries clears the dictionaries of the dictionary builders of f and its
+// descendants, keeping Avro enum symbols.
+func (f *fieldPos) resetDictionaries() {
+	if db, ok := f.builder.(*array.BinaryDictionaryBuilder); ok {
+		db.ResetFull()
+		if f.dictValues != nil {
+			db.InsertStringDictValues(f.dictValues)
+		}
+	}
+	for _, c := range f.childrens {
+		c.resetDictionaries()
+	}
+}
+
 func (f *fieldPos) buildNamePath() []string {
 	var path []string
 
@@ -391,7 +440,11 @@ func (f *fieldPos) getValue(m any) any {
 //
 // mapFieldBuilders builds a tree of field builders matching the Arrow schema
 func mapFieldBuilders(b array.Builder, field arrow.Field, parent *fieldPos) {
-	f := parent.newChild(field.Name, b, field.Metadata)
+	name := field.Name
+	if orig, ok := field.Metadata.GetValue(OriginalNameKey); ok {
+		name = orig
+	}
+	f := parent.newChild(name, b, field.Metadata)
 	switch bt := b.(type) {
 	case *array.BinaryBuilder:
 		f.appendFunc = func(data interface{}) error {
@@ -401,8 +454,7 @@ func mapFieldBuilders(b array.Builder, field arrow.Field, parent *fieldPos) {
 	case *array.BinaryDictionaryBuilder:
 		// has metadata for Avro enum symbols
 		f.appendFunc = func(data interface{}) error {
-			appendBinaryDictData(bt, data, f.source)
-			return nil
+			return appendBinaryDictData(bt, data, f.source, f.utf8Policy)
 		}
 		// add Avro enum symbols to builder
 		sb := array.NewStringBuilder(memory.DefaultAllocator)
@@ -411,16 +463,22 @@ func mapFieldBuilders(b array.Builder, field arrow.Field, parent *fieldPos) {
 		}
 		sa := sb.NewStringArray()
 		bt.InsertStringDictValues(sa)
+		// kept to restore the symbols when the dictionary is reset
+		f.dictValues = sa
 	case *array.BooleanBuilder:
 		f.appendFunc = func(data interface{}) error {
-			appendBoolData(bt, data, f.source)
+			appendBoolData(bt, data, f.source, f.boolTokens)
 			return nil
 		}
 	case *array.Date32Builder:
 		f.appendFunc = func(data interface{}) error {
-			appendDate32Data(bt, data, f.source)
+			appendDate32Data(bt, data, f.source, f.timeLayouts)
 			return nil
 		}
+	case *array.Date64Builder:
+		f.appendFunc = func(data interface{}) error {
+			return appendDate64Data(bt, data, f.source, f.timeLayouts)
+		}
 	case *array.Decimal128Builder:
 		f.appendFunc = func(data interface{}) error {
 			err := appendDecimal128Data(bt, data, f.source)
@@ -470,15 +528,37 @@ func mapFieldBuilders(b array.Builder, field arrow.Field, parent *fieldPos) {
 			appendFloat64Data(bt, data, f.source)
 			return nil
 		}
+	case *array.Int8Builder:
+		f.appendFunc = func(data interface{}) error {
+			return appendInt8Data(bt, data, f.source)
+		}
+	case *array.Int16Builder:
+		f.appendFunc = func(data interface{}) error {
+			return appendInt16Data(bt, data, f.source)
+		}
 	case *array.Int32Builder:
 		f.appendFunc = func(data interface{}) error {
-			appendInt32Data(bt, data, f.source)
-			return nil
+			return appendInt32Data(bt, data, f.source)
 		}
 	case *array.Int64Builder:
 		f.appendFunc = func(data interface{}) error {
-			appendInt64Data(bt, data, f.source)
-			return nil
+			return appendInt64Data(bt, data, f.source)
+		}
+	case *array.Uint8Builder:
+		f.appendFunc = func(data interface{}) error {
+			return appendUint8Data(bt, data, f.source)
+		}
+	case *array.Uint16Builder:
+		f.appendFunc = func(data interface{}) error {
+			return appendUint16Data(bt, data, f.source)
+		}
+	case *array.Uint32Builder:
+		f.appendFunc = func(data interface{}) error {
+			return appendUint32Data(bt, data, f.source)
+		}
+	case *array.Uint64Builder:
+		f.appendFunc = func(data interface{}) error {
+			return appendUint64Data(bt, data, f.source)
 		}
 	case *array.LargeListBuilder:
 		vb := bt.ValueBuilder()
@@ -518,6 +598,40 @@ func mapFieldBuilders(b array.Builder, field arrow.Field, parent *fieldPos) {
 			}
 			return nil
 		}
+	case *array.FixedSizeListBuilder:
+		vb := bt.ValueBuilder()
+		f.isList = true
+		f.fixedLen = field.Type.(*arrow.FixedSizeListType).Len()
+		mapFieldBuilders(vb, field.Type.(*arrow.FixedSizeListType).ElemField(), f)
+		f.appendFunc = func(data interface{}) error {
+			switch data.(type) {
+			case nil:
+				// appends fixedLen null values
+				bt.AppendNull()
+			default:
+				bt.Append(true)
+			}
+			return nil
+		}
+	case *array.RunEndEncodedBuilder:
+		// the values builder is loaded through f, not as a field of its own
+		mapFieldBuilders(bt.ValueBuilder(), arrow.Field{Name: name, Type: field.Type.(*arrow.RunEndEncodedType).Encoded(), Nullable: true}, f)
+		values := f.childrens[len(f.childrens)-1]
+		f.childrens = f.childrens[:len(f.childrens)-1]
+		var last any
+		f.appendFunc = func(data interface{}) error {
+			if bt.Len() > 0 && sameRun(last, data) {
+				bt.ContinueRun(1)
+				return nil
+			}
+			last = data
+			if data == nil {
+				bt.AppendNull()
+				return nil
+			}
+			bt.Append(1)
+			return values.appendFunc(data)
+		}
 	case *array.MapBuilder:
 		// has metadata for objects in values
 		f.isMap = true
@@ -534,6 +648,16 @@ func mapFieldBuilders(b array.Builder, field arrow.Field, parent *fieldPos) {
 			}
 			return nil
 		}
+	case *array.NullBuilder:
+		f.appendFunc = func(data interface{}) error {
+			bt.AppendNull()
+			return nil
+		}
+	case *array.DurationBuilder:
+		unit := field.Type.(*arrow.DurationType).Unit
+		f.appendFunc = func(data interface{}) error {
+			return appendDurationUnitData(bt, data, unit)
+		}
 	case *array.MonthDayNanoIntervalBuilder:
 		f.appendFunc = func(data interface{}) error {
 			appendDurationData(bt, data, f.source)
@@ -541,8 +665,11 @@ func mapFieldBuilders(b array.Builder, field arrow.Field, parent *fieldPos) {
 		}
 	case *array.StringBuilder:
 		f.appendFunc = func(data interface{}) error {
-			appendStringData(bt, data, f.source)
-			return nil
+			return appendStringData(bt, data, f.source, f.utf8Policy)
+		}
+	case *array.LargeStringBuilder:
+		f.appendFunc = func(data interface{}) error {
+			return appendLargeStringData(bt, data, f.source, f.utf8Policy)
 		}
 	case *array.StructBuilder:
 		// has metadata for Avro Union named types
@@ -574,10 +701,38 @@ func mapFieldBuilders(b array.Builder, field arrow.Field, parent *fieldPos) {
 		}
 	case *array.TimestampBuilder:
 		f.appendFunc = func(data interface{}) error {
-			appendTimestampData(bt, data, f.source)
+			appendTimestampData(bt, data, f.source, f.timeLayouts, f.location)
 			return nil
 		}
 	}
+	if f.avroTypes != nil && f.source == DataSourceAvro && f.appendFunc != nil {
+		f.appendFunc = f.avroTypes.wrap(f, field, f.appendFunc)
+	}
+	if _, ok := field.Metadata.GetValue(JSONBlobKey); ok && f.appendFunc != nil {
+		f.appendFunc = jsonBlob(f.appendFunc)
+	}
+	if v, ok := f.defaults[f.dotPath()]; ok && f.appendFunc != nil && !f.isItem {
+		f.appendFunc = withDefault(f.appendFunc, v)
+	}
+	if f.blankAsNull && f.appendFunc != nil {
+		f.appendFunc = blankAsNull(f.appendFunc)
+	}
+	if f.appendFunc != nil {
+		f.appendFunc = appendOrNull(b, f.appendFunc)
+	}
+}
+
+// appendOrNull wraps fn so that null is appended to b if fn appended no value, ie. when
+// it failed, keeping the columns of the row being loaded of equal length.
+func appendOrNull(b array.Builder, fn func(val interface{}) error) func(val interface{}) error {
+	return func(data interface{}) error {
+		n := b.Len()
+		err := fn(data)
+		if b.Len() == n {
+			b.AppendNull()
+		}
+		return err
+	}
 }
 
 func appendBinaryData(b *array.BinaryBuilder, data any, source DataSource) {
@@ -600,30 +755,59 @@ func appendBinaryData(b *array.BinaryBuilder, data any, source DataSource) {
 	}
 }
 
-func appendBinaryDictData(b *array.BinaryDictionaryBuilder, data any, source DataSource) {
+func appendBinaryDictData(b *array.BinaryDictionaryBuilder, data any, source DataSource, policy InvalidUTF8Policy) error {
+	var s string
 	switch dt := data.(type) {
 	case nil:
 		b.AppendNull()
+		return nil
 	case string:
-		b.AppendString(dt)
+		s = dt
 	case map[string]any:
-		if source == DataSourceAvro {
-			switch v := dt["string"].(type) {
-			case nil:
-				b.AppendNull()
-			case string:
-				b.AppendString(v)
-			}
+		if source != DataSourceAvro {
+			return nil
 		}
+		switch v := dt["string"].(type) {
+		case nil:
+			b.AppendNull()
+			return nil
+		case string:
+			s = v
+		default:
+			return nil
+		}
+	default:
+		return nil
 	}
+	s, null, err := checkUTF8(s, policy)
+	if err != nil || null {
+		// a value rejected by the policy is loaded as null
+		b.AppendNull()
+		return err
+	}
+	return b.AppendString(s)
 }
 
-func appendBoolData(b *array.BooleanBuilder, data any, source DataSource) {
+func appendBoolData(b *array.BooleanBuilder, data any, source DataSource, tokens map[string]bool) {
 	switch dt := data.(type) {
 	case nil:
 		b.AppendNull()
 	case bool:
 		b.Append(dt)
+	case string:
+		if v, ok := tokens[dt]; ok {
+			b.Append(v)
+		} else if v, err := strconv.ParseBool(dt); err == nil {
+			b.Append(v)
+		} else {
+			b.AppendNull()
+		}
+	case json.Number:
+		if v, ok := tokens[dt.String()]; ok {
+			b.Append(v)
+		} else {
+			b.AppendNull()
+		}
 	case map[string]any:
 		if source == DataSourceAvro {
 			switch v := dt["boolean"].(type) {
@@ -636,14 +820,19 @@ func appendBoolData(b *array.BooleanBuilder, data any, source DataSource) {
 	}
 }
 
-func appendDate32Data(b *array.Date32Builder, data any, source DataSource) {
+func appendDate32Data(b *array.Date32Builder, data any, source DataSource, layouts []string) {
 	switch dt := data.(type) {
 	case nil:
 		b.AppendNull()
 	case json.Number:
 		// TO-DO
 	case string:
-		date, _ := time.Parse(time.DateOnly, dt)
+		date, err := time.Parse(time.DateOnly, dt)
+		if err != nil {
+			if t, ok := parseTimeLayouts(dt, layouts); ok {
+				date = t
+			}
+		}
 		b.Append(arrow.Date32FromTime(date))
 	case time.Time:
 		b.Append(arrow.Date32FromTime(dt))
@@ -661,10 +850,66 @@ func appendDate32Data(b *array.Date32Builder, data any, source DataSource) {
 	}
 }
 
+// appendDate64Data appends an epoch milliseconds number or a date string to a date64
+// column, truncated to the start of its day in UTC.
+func appendDate64Data(b *array.Date64Builder, data any, source DataSource, layouts []string) error {
+	switch dt := data.(type) {
+	case nil:
+		b.AppendNull()
+	case json.Number:
+		ms, err := dt.Int64()
+		if err != nil {
+			b.AppendNull()
+			return fmt.Errorf("%w : %v", ErrInvalidDate64Data, dt)
+		}
+		b.Append(arrow.Date64FromTime(time.UnixMilli(ms).UTC()))
+	case string:
+		if ms, err := strconv.ParseInt(dt, 10, 64); err == nil {
+			b.Append(arrow.Date64FromTime(time.UnixMilli(ms).UTC()))
+			return nil
+		}
+		date, err := time.Parse(time.DateOnly, dt)
+		if err != nil {
+			t, ok := parseTimeLayouts(dt, layouts)
+			if !ok {
+				b.AppendNull()
+				return fmt.Errorf("%w : %q", ErrInvalidDate64Data, dt)
+			}
+			date = t
+		}
+		b.Append(arrow.Date64FromTime(date.UTC()))
+	case time.Time:
+		b.Append(arrow.Date64FromTime(dt.UTC()))
+	case int64:
+		b.Append(arrow.Date64FromTime(time.UnixMilli(dt).UTC()))
+	case int:
+		b.Append(arrow.Date64FromTime(time.UnixMilli(int64(dt)).UTC()))
+	case map[string]any:
+		if v, ok := dt["long"].(int64); ok && source == DataSourceAvro {
+			b.Append(arrow.Date64FromTime(time.UnixMilli(v).UTC()))
+			return nil
+		}
+		b.AppendNull()
+	default:
+		b.AppendNull()
+	}
+	return nil
+}
+
 func appendDecimal128Data(b *array.Decimal128Builder, data any, source DataSource) error {
 	switch dt := data.(type) {
 	case nil:
 		b.AppendNull()
+	case json.Number:
+		return appendDecimal128String(b, dt.String())
+	case string:
+		return appendDecimal128String(b, dt)
+	case int:
+		b.Append(decimal128.FromI64(int64(dt)))
+	case int64:
+		b.Append(decimal128.FromI64(dt))
+	case uint64:
+		b.Append(decimal128.FromU64(dt))
 	case []byte:
 		// TO-DO
 		if source == DataSourceAvro {
@@ -700,10 +945,29 @@ func appendDecimal128Data(b *array.Decimal128Builder, data any, source DataSourc
 	return nil
 }
 
+// appendDecimal128String appends the decimal number s, ie. an integer beyond the range
+// of int64.
+func appendDecimal128String(b *array.Decimal128Builder, s string) error {
+	dt := b.Type().(*arrow.Decimal128Type)
+	n, err := decimal128.FromString(s, dt.Precision, dt.Scale)
+	if err != nil {
+		return fmt.Errorf("%w : %v", ErrInvalidIntegerData, err)
+	}
+	b.Append(n)
+	return nil
+}
+
 func appendDecimal256Data(b *array.Decimal256Builder, data any, source DataSource) error {
 	switch dt := data.(type) {
 	case nil:
 		b.AppendNull()
+	case string:
+		t := b.Type().(*arrow.Decimal256Type)
+		n, err := decimal256.FromString(dt, t.Precision, t.Scale)
+		if err != nil {
+			return fmt.Errorf("%w : %v", ErrInvalidIntegerData, err)
+		}
+		b.Append(n)
 	case []byte:
 		// TO-DO
 		if source == DataSourceAvro {
@@ -758,6 +1022,38 @@ func appendDurationData(b *array.MonthDayNanoIntervalBuilder, data any, source D
 	}
 }
 
+// appendDurationUnitData appends an ISO 8601 duration string, or a number of units,
+// to a duration column of unit.
+func appendDurationUnitData(b *array.DurationBuilder, data any, unit arrow.TimeUnit) error {
+	switch dt := data.(type) {
+	case nil:
+		b.AppendNull()
+	case string:
+		d, err := ParseISODuration(dt)
+		if err != nil {
+			b.AppendNull()
+			return err
+		}
+		b.Append(arrow.Duration(d / unit.Multiplier()))
+	case json.Number:
+		i, err := dt.Int64()
+		if err != nil {
+			b.AppendNull()
+			return err
+		}
+		b.Append(arrow.Duration(i))
+	case time.Duration:
+		b.Append(arrow.Duration(dt / unit.Multiplier()))
+	case int64:
+		b.Append(arrow.Duration(dt))
+	case int:
+		b.Append(arrow.Duration(dt))
+	default:
+		b.AppendNull()
+	}
+	return nil
+}
+
 func appendFixedSizeBinaryData(b *array.FixedSizeBinaryBuilder, data any, source DataSource) {
 	switch dt := data.(type) {
 	case nil:
@@ -824,64 +1120,55 @@ func appendFloat64Data(b *array.Float64Builder, data any, source DataSource) {
 	}
 }
 
-func appendInt8Data(b *array.Int8Builder, data any, source DataSource) {
+func appendInt8Data(b *array.Int8Builder, data any, source DataSource) error {
 	switch dt := data.(type) {
 	case nil:
 		b.AppendNull()
-	case int:
-		b.Append(int8(dt))
-	case int8:
-		b.Append(dt)
-	case json.Number:
-		i, _ := dt.Int64()
-		b.Append(int8(i))
-	case string:
-		i, _ := strconv.ParseInt(dt, 10, 8)
-		b.Append(int8(i))
 	case map[string]any:
-
+	default:
+		i, err := intData(dt, 8)
+		if err != nil {
+			b.AppendNull()
+			return err
+		}
+		b.Append(int8(i))
 	}
+	return nil
 }
 
-func appendInt16Data(b *array.Int16Builder, data any, source DataSource) {
+func appendInt16Data(b *array.Int16Builder, data any, source DataSource) error {
 	switch dt := data.(type) {
 	case nil:
 		b.AppendNull()
-	case int:
-		b.Append(int16(dt))
-	case int16:
-		b.Append(dt)
-	case json.Number:
-		i, _ := dt.Int64()
-		b.Append(int16(i))
-	case string:
-		i, _ := strconv.ParseInt(dt, 10, 16)
-		b.Append(int16(i))
 	case map[string]any:
-
+	default:
+		i, err := intData(dt, 16)
+		if err != nil {
+			b.AppendNull()
+			return err
+		}
+		b.Append(int16(i))
 	}
+	return nil
 }
 
-func appendInt32Data(b *array.Int32Builder, data any, source DataSource) {
+func appendInt32Data(b *array.Int32Builder, data any, source DataSource) error {
 	switch dt := data.(type) {
 	case nil:
 		b.AppendNull()
-	case int:
-		b.Append(int32(dt))
-	case int32:
-		b.Append(dt)
-	case json.Number:
-		i, _ := dt.Int64()
-		b.Append(int32(i))
-	case string:
-		i, _ := strconv.ParseInt(dt, 10, 32)
-		b.Append(int32(i))
 	case map[string]any:
-
+	default:
+		i, err := intData(dt, 32)
+		if err != nil {
+			b.AppendNull()
+			return err
+		}
+		b.Append(int32(i))
 	}
+	return nil
 }
 
-func appendInt64Data(b *array.Int64Builder, data any, source DataSource) {
+func appendInt64Data(b *array.Int64Builder, data any, source DataSource) error {
 	switch dt := data.(type) {
 	case nil:
 		b.AppendNull()
@@ -889,11 +1176,26 @@ func appendInt64Data(b *array.Int64Builder, data any, source DataSource) {
 		b.Append(int64(dt))
 	case int64:
 		b.Append(dt)
+	case bool:
+		// a column mixing booleans and integers
+		if dt {
+			b.Append(1)
+		} else {
+			b.Append(0)
+		}
 	case string:
-		i, _ := strconv.ParseInt(dt, 10, 64)
+		i, err := strconv.ParseInt(dt, 10, 64)
+		if errors.Is(err, strconv.ErrRange) {
+			b.AppendNull()
+			return fmt.Errorf("%w : %v overflows int64", ErrInvalidIntegerData, dt)
+		}
 		b.Append(i)
 	case json.Number:
-		i, _ := dt.Int64()
+		i, err := dt.Int64()
+		if errors.Is(err, strconv.ErrRange) {
+			b.AppendNull()
+			return fmt.Errorf("%w : %v overflows int64", ErrInvalidIntegerData, dt)
+		}
 		b.Append(i)
 	case map[string]any:
 		if source == DataSourceAvro {
@@ -907,26 +1209,262 @@ func appendInt64Data(b *array.Int64Builder, data any, source DataSource) {
 			}
 		}
 	}
+	return nil
 }
 
-func appendStringData(b *array.StringBuilder, data any, source DataSource) {
+func appendUint8Data(b *array.Uint8Builder, data any, source DataSource) error {
 	switch dt := data.(type) {
 	case nil:
 		b.AppendNull()
+	default:
+		i, err := uintData(dt, 8)
+		if err != nil {
+			b.AppendNull()
+			return err
+		}
+		b.Append(uint8(i))
+	}
+	return nil
+}
+
+func appendUint16Data(b *array.Uint16Builder, data any, source DataSource) error {
+	switch dt := data.(type) {
+	case nil:
+		b.AppendNull()
+	default:
+		i, err := uintData(dt, 16)
+		if err != nil {
+			b.AppendNull()
+			return err
+		}
+		b.Append(uint16(i))
+	}
+	return nil
+}
+
+func appendUint32Data(b *array.Uint32Builder, data any, source DataSource) error {
+	switch dt := data.(type) {
+	case nil:
+		b.AppendNull()
+	default:
+		i, err := uintData(dt, 32)
+		if err != nil {
+			b.AppendNull()
+			return err
+		}
+		b.Append(uint32(i))
+	}
+	return nil
+}
+
+func appendUint64Data(b *array.Uint64Builder, data any, source DataSource) error {
+	switch dt := data.(type) {
+	case nil:
+		b.AppendNull()
+	default:
+		i, err := uintData(dt, 64)
+		if err != nil {
+			b.AppendNull()
+			return err
+		}
+		b.Append(i)
+	}
+	return nil
+}
+
+// intData returns an integer value which must fit in a signed integer of bitSize bits.
+func intData(data any, bitSize int) (int64, error) {
+	var i int64
+	switch dt := data.(type) {
+	case int:
+		i = int64(dt)
+	case int8:
+		i = int64(dt)
+	case int16:
+		i = int64(dt)
+	case int32:
+		i = int64(dt)
+	case int64:
+		i = dt
+	case bool:
+		// a column mixing booleans and integers
+		if dt {
+			i = 1
+		}
+	case json.Number:
+		return parseInt(dt.String(), bitSize)
 	case string:
-		b.Append(dt)
+		return parseInt(dt, bitSize)
+	default:
+		return 0, fmt.Errorf("%w : %v is not an integer", ErrInvalidIntegerData, data)
+	}
+	if bitSize < 64 && (i < -1<<(bitSize-1) || i > 1<<(bitSize-1)-1) {
+		return 0, fmt.Errorf("%w : %v overflows int%d", ErrInvalidIntegerData, data, bitSize)
+	}
+	return i, nil
+}
+
+// uintData returns an integer value which must fit in an unsigned integer of bitSize bits.
+func uintData(data any, bitSize int) (uint64, error) {
+	var i uint64
+	switch dt := data.(type) {
+	case int:
+		if dt < 0 {
+			return 0, fmt.Errorf("%w : %v overflows uint%d", ErrInvalidIntegerData, data, bitSize)
+		}
+		i = uint64(dt)
+	case int64:
+		if dt < 0 {
+			return 0, fmt.Errorf("%w : %v overflows uint%d", ErrInvalidIntegerData, data, bitSize)
+		}
+		i = uint64(dt)
+	case uint:
+		i = uint64(dt)
+	case uint8:
+		i = uint64(dt)
+	case uint16:
+		i = uint64(dt)
+	case uint32:
+		i = uint64(dt)
+	case uint64:
+		i = dt
+	case json.Number:
+		return parseUint(dt.String(), bitSize)
+	case string:
+		return parseUint(dt, bitSize)
+	default:
+		return 0, fmt.Errorf("%w : %v is not an integer", ErrInvalidIntegerData, data)
+	}
+	if bitSize < 64 && i > 1<<bitSize-1 {
+		return 0, fmt.Errorf("%w : %v overflows uint%d", ErrInvalidIntegerData, data, bitSize)
+	}
+	return i, nil
+}
+
+// parseInt parses s as a signed integer of bitSize bits, wrapping its error in
+// ErrInvalidIntegerData.
+func parseInt(s string, bitSize int) (int64, error) {
+	i, err := strconv.ParseInt(s, 10, bitSize)
+	if err != nil {
+		return 0, fmt.Errorf("%w : %v", ErrInvalidIntegerData, err)
+	}
+	return i, nil
+}
+
+// parseUint parses s as an unsigned integer of bitSize bits, wrapping its error in
+// ErrInvalidIntegerData.
+func parseUint(s string, bitSize int) (uint64, error) {
+	i, err := strconv.ParseUint(s, 10, bitSize)
+	if err != nil {
+		return 0, fmt.Errorf("%w : %v", ErrInvalidIntegerData, err)
+	}
+	return i, nil
+}
+
+func appendStringData(b *array.StringBuilder, data any, source DataSource, policy InvalidUTF8Policy) error {
+	var v string
+	switch dt := data.(type) {
+	case nil:
+		b.AppendNull()
+		return nil
+	case string:
+		v = dt
+	case json.Number:
+		// the number's input text, ie. 1.10
+		v = dt.String()
 	case map[string]any:
 		if source == DataSourceAvro {
-			switch v := dt["string"].(type) {
+			switch sv := dt["string"].(type) {
 			case nil:
 				b.AppendNull()
+				return nil
 			case string:
-				b.Append(v)
+				v = sv
+			default:
+				return nil
 			}
+		} else {
+			v = jsonString(dt)
 		}
+	case []any:
+		v = jsonString(dt)
 	default:
-		b.Append(fmt.Sprint(data))
+		v = fmt.Sprint(data)
+	}
+	v, null, err := checkUTF8(v, policy)
+	if err != nil || null {
+		// a value rejected by the policy is loaded as null
+		b.AppendNull()
+		return err
 	}
+	b.Append(v)
+	return nil
+}
+
+func appendLargeStringData(b *array.LargeStringBuilder, data any, source DataSource, policy InvalidUTF8Policy) error {
+	var v string
+	switch dt := data.(type) {
+	case nil:
+		b.AppendNull()
+		return nil
+	case string:
+		v = dt
+	case json.Number:
+		// the number's input text, ie. 1.10
+		v = dt.String()
+	case map[string]any:
+		if source == DataSourceAvro {
+			switch sv := dt["string"].(type) {
+			case nil:
+				b.AppendNull()
+				return nil
+			case string:
+				v = sv
+			default:
+				return nil
+			}
+		} else {
+			v = jsonString(dt)
+		}
+	case []any:
+		v = jsonString(dt)
+	default:
+		v = fmt.Sprint(data)
+	}
+	v, null, err := checkUTF8(v, policy)
+	if err != nil || null {
+		// a value rejected by the policy is loaded as null
+		b.AppendNull()
+		return err
+	}
+	b.Append(v)
+	return nil
+}
+
+// parseTimeLayouts parses s with the first of layouts that matches it, in UTC if
+// the layout has no time zone.
+func parseTimeLayouts(s string, layouts []string) (time.Time, bool) {
+	return parseTimeLayoutsIn(s, layouts, time.UTC)
+}
+
+// parseTimeLayoutsIn parses s with the first of layouts that matches it, in loc if
+// the layout has no time zone.
+func parseTimeLayoutsIn(s string, layouts []string, loc *time.Location) (time.Time, bool) {
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// jsonString returns the JSON encoding of a nested value loaded into a string column.
+func jsonString(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
 }
 
 func appendTime32Data(b *array.Time32Builder, data any, source DataSource) {
@@ -971,7 +1509,9 @@ func appendTime64Data(b *array.Time64Builder, data any, source DataSource) {
 	}
 }
 
-func appendTimestampData(b *array.TimestampBuilder, data any, source DataSource) {
+// appendTimestampData appends data to b, interpreting strings without a time zone in
+// loc if it is not nil, otherwise in UTC.
+func appendTimestampData(b *array.TimestampBuilder, data any, source DataSource, layouts []string, loc *time.Location) {
 	switch dt := data.(type) {
 	case nil:
 		b.AppendNull()
@@ -980,7 +1520,19 @@ func appendTimestampData(b *array.TimestampBuilder, data any, source DataSource)
 		t, _ := arrow.TimestampFromTime(time.Unix(epochSeconds, 0), arrow.Microsecond)
 		b.Append(t)
 	case string:
-		t, _ := arrow.TimestampFromString(dt, arrow.Microsecond)
+		var t arrow.Timestamp
+		var err error
+		if loc != nil {
+			t, _, err = arrow.TimestampFromStringInLocation(dt, arrow.Microsecond, loc)
+		} else {
+			loc = time.UTC
+			t, err = arrow.TimestampFromString(dt, arrow.Microsecond)
+		}
+		if err != nil {
+			if pt, ok := parseTimeLayoutsIn(dt, layouts, loc); ok {
+				t, _ = arrow.TimestampFromTime(pt, arrow.Microsecond)
+			}
+		}
 		b.Append(t)
 	case time.Time:
 		t, _ := arrow.TimestampFromTime(dt, arrow.Microsecond)
@@ -0,0 +1,98 @@
+package reader
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+)
+
+func TestLoadIntegerOverflow(t *testing.T) {
+	tests := []struct {
+		typ      arrow.DataType
+		ok, over string
+	}{
+		{arrow.PrimitiveTypes.Int8, "-128", "128"},
+		{arrow.PrimitiveTypes.Int8, "127", "-129"},
+		{arrow.PrimitiveTypes.Int16, "-32768", "32768"},
+		{arrow.PrimitiveTypes.Int32, "2147483647", "-2147483649"},
+		{arrow.PrimitiveTypes.Int64, "9223372036854775807", "9223372036854775808"},
+		{arrow.PrimitiveTypes.Uint8, "255", "256"},
+		{arrow.PrimitiveTypes.Uint8, "0", "-1"},
+		{arrow.PrimitiveTypes.Uint16, "65535", "65536"},
+		{arrow.PrimitiveTypes.Uint32, "4294967295", "4294967296"},
+		{arrow.PrimitiveTypes.Uint64, "18446744073709551615", "18446744073709551616"},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%v/%s", tt.typ, tt.over), func(t *testing.T) {
+			schema := arrow.NewSchema([]arrow.Field{
+				{Name: "a", Type: arrow.PrimitiveTypes.Uint8, Nullable: true},
+				{Name: "b", Type: tt.typ, Nullable: true},
+				{Name: "c", Type: arrow.BinaryTypes.String, Nullable: true},
+			}, nil)
+			r, err := NewReader(schema, DataSourceJSON)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer r.Close()
+			_, err = r.ReadToRecord([]byte(`{"a":1,"b":` + tt.over + `,"c":"x"}`))
+			if !errors.Is(err, ErrInvalidIntegerData) {
+				t.Fatalf("ReadToRecord(%s) error = %v, want ErrInvalidIntegerData", tt.over, err)
+			}
+			// the failed row was discarded, the next datum loads alone
+			rec, err := r.ReadToRecord([]byte(`{"a":2,"b":` + tt.ok + `,"c":"y"}`))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer rec.Release()
+			if rec.NumRows() != 1 {
+				t.Fatalf("record has %d rows, want 1", rec.NumRows())
+			}
+			if got := rec.Column(0).(*array.Uint8).Value(0); got != 2 {
+				t.Errorf("a = %d, want 2", got)
+			}
+			if got := rec.Column(1).ValueStr(0); got != tt.ok {
+				t.Errorf("b = %s, want %s", got, tt.ok)
+			}
+			if r.Err() != nil {
+				t.Errorf("Err() = %v", r.Err())
+			}
+		})
+	}
+}
+
+func TestLoadInvalidDate64(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "d", Type: arrow.FixedWidthTypes.Date64, Nullable: true},
+		{Name: "n", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+	}, nil)
+	r, err := NewReader(schema, DataSourceJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	for _, in := range []string{`{"d":"yesterday","n":1}`, `{"d":"1.5","n":1}`} {
+		if _, err := r.ReadToRecord([]byte(in)); !errors.Is(err, ErrInvalidDate64Data) {
+			t.Fatalf("ReadToRecord(%s) error = %v, want ErrInvalidDate64Data", in, err)
+		}
+	}
+	rec, err := r.ReadToRecord([]byte(`{"d":"2024-02-29","n":2}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rec.Release()
+	if rec.NumRows() != 1 || rec.Column(0).ValueStr(0) != "2024-02-29" || rec.Column(1).ValueStr(0) != "2" {
+		t.Errorf("ReadToRecord() after invalid dates = %v", rec)
+	}
+	// an object is loaded as null
+	rec2, err := r.ReadToRecord([]byte(`{"d":{"long":1},"n":3}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rec2.Release()
+	if rec2.NumRows() != 1 || rec2.Column(0).IsValid(0) {
+		t.Errorf("ReadToRecord() of an object date = %v", rec2)
+	}
+}
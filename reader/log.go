@@ -0,0 +1,15 @@
+package reader
+
+// Levels of the events passed to the logger set with WithLogger.
+const (
+	LogDebug = "debug"
+	LogWarn  = "warn"
+	LogError = "error"
+)
+
+// log passes an internal event to the logger set with WithLogger, if any.
+func (r *DataReader) log(level, msg string, kv ...any) {
+	if r.logger != nil {
+		r.logger(level, msg, kv...)
+	}
+}
@@ -0,0 +1,64 @@
+package reader
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+var ErrMemoryLimit = errors.New("memory limit exceeded")
+
+// memoryLimitPanic is raised by a limitAllocator refusing an allocation.
+type memoryLimitPanic struct{}
+
+// limitAllocator tracks the bytes in use through an allocator and, while enforcing,
+// refuses allocations which would exceed its limit by panicking with memoryLimitPanic,
+// as an allocator can't return an error. It only enforces the limit while a datum is
+// loaded, where the panic is recovered.
+type limitAllocator struct {
+	memory.Allocator
+	limit     int64
+	inUse     atomic.Int64
+	enforcing atomic.Bool
+}
+
+func (a *limitAllocator) Allocate(size int) []byte {
+	a.grow(int64(size))
+	return a.Allocator.Allocate(size)
+}
+
+func (a *limitAllocator) Reallocate(size int, b []byte) []byte {
+	a.grow(int64(size - len(b)))
+	return a.Allocator.Reallocate(size, b)
+}
+
+func (a *limitAllocator) Free(b []byte) {
+	a.inUse.Add(-int64(len(b)))
+	a.Allocator.Free(b)
+}
+
+// grow adds n bytes to the bytes in use, panicking if they would exceed the limit.
+func (a *limitAllocator) grow(n int64) {
+	if n > 0 && a.enforcing.Load() && a.inUse.Load()+n > a.limit {
+		panic(memoryLimitPanic{})
+	}
+	a.inUse.Add(n)
+}
+
+// recovered returns an error wrapping ErrMemoryLimit if rc, a recovered panic, was
+// raised by the allocator, after discarding the rows of bld, which were left part
+// way through a datum. Other panics are raised again.
+func (a *limitAllocator) recovered(rc any, bld *array.RecordBuilder) error {
+	if _, ok := rc.(memoryLimitPanic); !ok {
+		panic(rc)
+	}
+	a.enforcing.Store(false)
+	// columns may differ in length, so they are discarded one by one
+	for _, fb := range bld.Fields() {
+		fb.NewArray().Release()
+	}
+	return fmt.Errorf("%w : %d bytes, record discarded", ErrMemoryLimit, a.limit)
+}
@@ -0,0 +1,44 @@
+package reader
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+func TestMemoryLimitContinues(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "s", Type: arrow.BinaryTypes.String, Nullable: true},
+	}, nil)
+	huge := `{"s":"` + strings.Repeat("x", 1<<20) + `"}`
+	input := strings.Join([]string{`{"s":"a"}`, `{"s":"b"}`, huge, `{"s":"c"}`, `{"s":"d"}`, `{"s":"e"}`}, "\n") + "\n"
+	var sink bytes.Buffer
+	r, err := NewReader(schema, DataSourceJSON, WithChunk(3), WithMemoryLimit(256<<10),
+		WithBadRecordSink(&sink), WithIOReader(strings.NewReader(input), '\n'))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	var got []string
+	for r.Next() {
+		rec := r.Record()
+		for i := 0; i < int(rec.NumRows()); i++ {
+			got = append(got, rec.Column(0).ValueStr(i))
+		}
+	}
+	if r.Err() != nil {
+		t.Fatalf("Err() = %v", r.Err())
+	}
+	// a and b were discarded with the record being built
+	if strings.Join(got, ",") != "c,d,e" {
+		t.Errorf("rows = %v, want [c d e]", got)
+	}
+	if m := r.Metrics(); m.DiscardedRecords != 3 || m.LoadErrors != 0 {
+		t.Errorf("DiscardedRecords = %d, LoadErrors = %d, want 3, 0", m.DiscardedRecords, m.LoadErrors)
+	}
+	if sink.Len() != len(huge)+1 {
+		t.Errorf("bad record sink holds %d bytes, want the %d bytes datum", sink.Len(), len(huge)+1)
+	}
+}
@@ -0,0 +1,57 @@
+package reader
+
+import (
+	"sync/atomic"
+
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// statsAllocator counts the bytes allocated through a memory.CheckedAllocator.
+type statsAllocator struct {
+	*memory.CheckedAllocator
+	allocated atomic.Int64
+}
+
+func (a *statsAllocator) Allocate(size int) []byte {
+	a.allocated.Add(int64(size))
+	return a.CheckedAllocator.Allocate(size)
+}
+
+func (a *statsAllocator) Reallocate(size int, b []byte) []byte {
+	if size > len(b) {
+		a.allocated.Add(int64(size - len(b)))
+	}
+	return a.CheckedAllocator.Reallocate(size, b)
+}
+
+// MemStats returns the total number of bytes allocated by the reader's record builders
+// and the number of bytes currently in use, when the reader was created with a
+// *memory.CheckedAllocator using WithAllocator. Zeros are returned for any other
+// allocator, which doesn't track its allocations.
+func (r *DataReader) MemStats() (allocated, inUse int64) {
+	if r.memStats == nil {
+		return 0, 0
+	}
+	return r.memStats.allocated.Load(), int64(r.memStats.CurrentAlloc())
+}
+
+// reallocCounter counts the reallocations growing the buffers of the reader's
+// record builders.
+type reallocCounter struct {
+	memory.Allocator
+	reallocs atomic.Int64
+}
+
+func (a *reallocCounter) Reallocate(size int, b []byte) []byte {
+	if size > len(b) {
+		a.reallocs.Add(1)
+	}
+	return a.Allocator.Reallocate(size, b)
+}
+
+// Realloc returns the number of times the reader's record builders had to grow their
+// buffers to date. Frequent growth can be avoided by reserving more rows with
+// WithReserve or WithChunk.
+func (r *DataReader) Realloc() int {
+	return int(r.reallocs.reallocs.Load())
+}
@@ -0,0 +1,123 @@
+package reader
+
+import (
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// readerCounters are the throughput counters of a DataReader, updated by its goroutines.
+type readerCounters struct {
+	started      time.Time
+	datum        atomic.Int64
+	bytes        atomic.Int64
+	decodeErrors atomic.Int64
+	loadErrors   atomic.Int64
+	records      atomic.Int64
+	rows         atomic.Int64
+	// datum skipped by WithSkipBadRecords
+	skipped atomic.Int64
+	// datum discarded with their record by WithMemoryLimit
+	discarded atomic.Int64
+	// arrays truncated by WithMaxArrayLength
+	truncatedArrays atomic.Int64
+}
+
+// countInput counts an input datum a, which could not be decoded if err is not nil.
+func (c *readerCounters) countInput(a any, err error) {
+	switch t := a.(type) {
+	case []byte:
+		c.bytes.Add(int64(len(t)))
+	case string:
+		c.bytes.Add(int64(len(t)))
+	}
+	if err != nil {
+		c.decodeErrors.Add(1)
+		return
+	}
+	c.datum.Add(1)
+}
+
+// ReaderMetrics is a snapshot of a DataReader's throughput, see DataReader.Metrics.
+type ReaderMetrics struct {
+	// Datum decoded from the io.Reader or passed to Read.
+	Datum int64
+	// Bytes of JSON datum decoded, for []byte and string input.
+	BytesDecoded int64
+	// Datum which could not be decoded.
+	DecodeErrors int64
+	// Datum which could not be loaded, stopping the reader.
+	LoadErrors int64
+	// Datum which could not be loaded and were skipped, with WithSkipBadRecords.
+	SkippedRecords int64
+	// Datum discarded as loading one exceeded the limit set with WithMemoryLimit: the
+	// datum itself and those loaded into the record being built, discarded with it.
+	DiscardedRecords int64
+	// Records emitted and their total number of rows.
+	Records, Rows int64
+	// Arrays truncated to the length set with WithMaxArrayLength.
+	TruncatedArrays int64
+	// Records and rows emitted per second since the reader was created.
+	RecordsPerSecond, RowsPerSecond float64
+	// Decoded datum waiting to be loaded and records waiting to be received,
+	// as returned by Peek.
+	InputQueue, RecordQueue int
+	// Time since the reader was created.
+	Elapsed time.Duration
+}
+
+// Metrics returns a snapshot of the reader's throughput counters, which can be polled
+// while it is reading, ie. to export to a monitoring system.
+func (r *DataReader) Metrics() ReaderMetrics {
+	m := ReaderMetrics{
+		Datum:            r.counters.datum.Load(),
+		BytesDecoded:     r.counters.bytes.Load(),
+		DecodeErrors:     r.counters.decodeErrors.Load(),
+		LoadErrors:       r.counters.loadErrors.Load(),
+		Records:          r.counters.records.Load(),
+		Rows:             r.counters.rows.Load(),
+		SkippedRecords:   r.counters.skipped.Load(),
+		DiscardedRecords: r.counters.discarded.Load(),
+		TruncatedArrays:  r.counters.truncatedArrays.Load(),
+		Elapsed:          time.Since(r.counters.started),
+	}
+	m.InputQueue, m.RecordQueue = r.Peek()
+	if secs := m.Elapsed.Seconds(); secs > 0 {
+		m.RecordsPerSecond = float64(m.Records) / secs
+		m.RowsPerSecond = float64(m.Rows) / secs
+	}
+	return m
+}
+
+// WritePrometheus writes the metrics in the Prometheus text exposition format, with
+// metric names prefixed by namespace followed by an underscore, ie. "bodkin", so that
+// they can be served from a /metrics endpoint or a node exporter textfile.
+func (m ReaderMetrics) WritePrometheus(w io.Writer, namespace string) error {
+	if namespace != "" {
+		namespace += "_"
+	}
+	for _, metric := range []struct {
+		name, kind, help string
+		value            float64
+	}{
+		{"reader_datum_total", "counter", "Datum decoded.", float64(m.Datum)},
+		{"reader_decoded_bytes_total", "counter", "Bytes of JSON datum decoded.", float64(m.BytesDecoded)},
+		{"reader_decode_errors_total", "counter", "Datum which could not be decoded.", float64(m.DecodeErrors)},
+		{"reader_load_errors_total", "counter", "Datum which could not be loaded.", float64(m.LoadErrors)},
+		{"reader_skipped_records_total", "counter", "Datum which could not be loaded and were skipped.", float64(m.SkippedRecords)},
+		{"reader_discarded_records_total", "counter", "Datum discarded for exceeding the memory limit.", float64(m.DiscardedRecords)},
+		{"reader_records_total", "counter", "Records emitted.", float64(m.Records)},
+		{"reader_rows_total", "counter", "Rows emitted.", float64(m.Rows)},
+		{"reader_truncated_arrays_total", "counter", "Arrays truncated to the maximum length.", float64(m.TruncatedArrays)},
+		{"reader_input_queue", "gauge", "Decoded datum waiting to be loaded.", float64(m.InputQueue)},
+		{"reader_record_queue", "gauge", "Records waiting to be received.", float64(m.RecordQueue)},
+	} {
+		_, err := fmt.Fprintf(w, "# HELP %[1]s%[2]s %[3]s\n# TYPE %[1]s%[2]s %[4]s\n%[1]s%[2]s %[5]v\n",
+			namespace, metric.name, metric.help, metric.kind, metric.value)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
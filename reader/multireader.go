@@ -0,0 +1,48 @@
+package reader
+
+import "io"
+
+// multiReader reads from a list of io.Readers in order. The data of each reader is
+// ended with the delimiter if it doesn't already end with it, so that a datum is
+// never carried over from one reader to the next.
+type multiReader struct {
+	readers []io.Reader
+	delim   byte
+	last    byte
+	read    bool
+	pending bool
+}
+
+func newMultiReader(readers []io.Reader, delim byte) *multiReader {
+	return &multiReader{readers: readers, delim: delim}
+}
+
+func (m *multiReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	for {
+		if m.pending {
+			m.pending = false
+			p[0] = m.delim
+			return 1, nil
+		}
+		if len(m.readers) == 0 {
+			return 0, io.EOF
+		}
+		n, err := m.readers[0].Read(p)
+		if n > 0 {
+			m.last = p[n-1]
+			m.read = true
+		}
+		if err == io.EOF {
+			m.pending = m.read && m.last != m.delim
+			m.readers = m.readers[1:]
+			m.read = false
+			err = nil
+		}
+		if n > 0 || err != nil {
+			return n, err
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package reader
+
+import (
+	"bufio"
+	stdjson "encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	json "github.com/goccy/go-json"
+)
+
+// WriteNDJSON writes each row of the records read by r to w as a JSON object followed
+// by a newline, the inverse of reading NDJSON, and returns the number of rows written.
+// Fields are written with their input names, structs as objects, lists as arrays,
+// maps as objects keyed by their keys' string form, timestamps as RFC 3339 strings,
+// dates as "2006-01-02" and JSON blob fields as the JSON they hold. Null fields are
+// written as null.
+func WriteNDJSON(w io.Writer, r *DataReader) (int, error) {
+	bw := bufio.NewWriter(w)
+	var n int
+	for r.Next() {
+		rec := r.Record()
+		fields := rec.Schema().Fields()
+		for i := 0; i < int(rec.NumRows()); i++ {
+			row := make(map[string]any, len(fields))
+			for j, f := range fields {
+				row[inputName(f)] = jsonValue(f, rec.Column(j), i)
+			}
+			b, err := json.Marshal(row)
+			if err != nil {
+				return n, fmt.Errorf("row %d : %w", n, err)
+			}
+			if _, err := bw.Write(append(b, '\n')); err != nil {
+				return n, err
+			}
+			n++
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return n, err
+	}
+	return n, r.Err()
+}
+
+// inputName returns the input data's name of field f.
+func inputName(f arrow.Field) string {
+	if orig, ok := f.Metadata.GetValue(OriginalNameKey); ok {
+		return orig
+	}
+	return f.Name
+}
+
+// jsonValue returns the value at index i of arr, of field f, in a form which marshals
+// to its natural JSON representation.
+func jsonValue(f arrow.Field, arr arrow.Array, i int) any {
+	if arr.IsNull(i) {
+		return nil
+	}
+	if _, ok := f.Metadata.GetValue(JSONBlobKey); ok {
+		if s, ok := arr.GetOneForMarshal(i).(string); ok && stdjson.Valid([]byte(s)) {
+			return stdjson.RawMessage(s)
+		}
+	}
+	switch a := arr.(type) {
+	case *array.Struct:
+		st := a.DataType().(*arrow.StructType)
+		m := make(map[string]any, st.NumFields())
+		for j, sf := range st.Fields() {
+			m[inputName(sf)] = jsonValue(sf, a.Field(j), i)
+		}
+		return m
+	case *array.Map:
+		start, end := a.ValueOffsets(i)
+		keys, items := a.Keys(), a.Items()
+		itemField := a.DataType().(*arrow.MapType).ItemField()
+		m := make(map[string]any, end-start)
+		for j := int(start); j < int(end); j++ {
+			m[keys.ValueStr(j)] = jsonValue(itemField, items, j)
+		}
+		return m
+	case array.ListLike:
+		start, end := a.ValueOffsets(i)
+		elem := a.DataType().(arrow.ListLikeType).ElemField()
+		values := a.ListValues()
+		l := make([]any, 0, end-start)
+		for j := int(start); j < int(end); j++ {
+			l = append(l, jsonValue(elem, values, j))
+		}
+		return l
+	case *array.Timestamp:
+		unit := a.DataType().(*arrow.TimestampType).Unit
+		return a.Value(i).ToTime(unit).Format(time.RFC3339Nano)
+	case *array.Date32:
+		return a.Value(i).ToTime().Format(time.DateOnly)
+	case *array.Date64:
+		return a.Value(i).ToTime().Format(time.DateOnly)
+	case *array.RunEndEncoded:
+		return jsonValue(f, a.Values(), a.GetPhysicalIndex(i))
+	}
+	return arr.GetOneForMarshal(i)
+}
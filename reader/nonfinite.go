@@ -0,0 +1,83 @@
+package reader
+
+import (
+	"bytes"
+	"slices"
+)
+
+// nonFiniteTokens are the non-finite float tokens recognized in JSON input, longest first.
+var nonFiniteTokens = []string{"-Infinity", "+Infinity", "Infinity", "-Inf", "+Inf", "Inf", "NaN"}
+
+// IsNonFiniteFloat reports whether s is one of the NaN, Infinity or Inf tokens.
+func IsNonFiniteFloat(s string) bool {
+	return slices.Contains(nonFiniteTokens, s)
+}
+
+// QuoteNonFiniteFloats returns JSON data with any bare NaN, Infinity or Inf tokens
+// quoted, so that data can be decoded as valid JSON. data is returned unchanged if
+// it contains no such tokens.
+func QuoteNonFiniteFloats(data []byte) []byte {
+	var out []byte
+	last := 0
+	inString, escaped := false, false
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case inString:
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+		case c == '"':
+			inString = true
+		case c == 'N' || c == 'I' || c == '-' || c == '+':
+			tok := nonFiniteToken(data[i:])
+			if tok == "" {
+				continue
+			}
+			out = append(out, data[last:i]...)
+			out = append(out, '"')
+			out = append(out, tok...)
+			out = append(out, '"')
+			i += len(tok) - 1
+			last = i + 1
+		}
+	}
+	if out == nil {
+		return data
+	}
+	return append(out, data[last:]...)
+}
+
+// nonFiniteToken returns the non-finite float token b starts with, if any.
+func nonFiniteToken(b []byte) string {
+	for _, tok := range nonFiniteTokens {
+		if !bytes.HasPrefix(b, []byte(tok)) {
+			continue
+		}
+		if len(b) > len(tok) && isTokenByte(b[len(tok)]) {
+			continue
+		}
+		return tok
+	}
+	return ""
+}
+
+func isTokenByte(c byte) bool {
+	return c == '_' || (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// quoteNonFinite quotes bare non-finite float tokens in JSON string or []byte input.
+func quoteNonFinite(a any) any {
+	switch t := a.(type) {
+	case []byte:
+		return QuoteNonFiniteFloats(t)
+	case string:
+		return string(QuoteNonFiniteFloats([]byte(t)))
+	}
+	return a
+}
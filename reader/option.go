@@ -3,6 +3,8 @@ package reader
 import (
 	"bufio"
 	"io"
+	"strings"
+	"time"
 
 	"github.com/apache/arrow-go/v18/arrow/memory"
 )
@@ -34,19 +36,93 @@ func WithChunk(n int) Option {
 	}
 }
 
+// WithFlushInterval makes a reader with a chunk size set with WithChunk emit the rows
+// loaded so far as a partial record when no datum arrived from the io.Reader for d, so
+// that a slow stream doesn't hold rows until the chunk is full. The interval is checked
+// with a ticker, so a partial record is emitted between d and 2*d after the last datum.
+func WithFlushInterval(d time.Duration) Option {
+	return func(cfg config) {
+		cfg.flushInterval = d
+	}
+}
+
 // WithIOReader provides an io.Reader to Bodkin Reader, along with a delimiter
 // to use to split datum in the data stream. Default delimiter '\n' if delimiter
 // is not provided.
 func WithIOReader(r io.Reader, delim byte) Option {
 	return func(cfg config) {
 		cfg.rr = r
-		cfg.br = bufio.NewReaderSize(cfg.rr, 1024*1024*16)
+		size := 1024 * 1024 * 16
+		if cfg.scanBufferSize > 0 {
+			size = cfg.scanBufferSize
+		}
+		cfg.br = bufio.NewReaderSize(cfg.rr, size)
 		if delim != DefaultDelimiter {
 			cfg.delim = delim
 		}
 	}
 }
 
+// WithScanBufferSize sets the size in bytes of the buffer used to read datum from the
+// io.Reader set with WithIOReader, 16MB by default.
+func WithScanBufferSize(n int) Option {
+	return func(cfg config) {
+		cfg.scanBufferSize = n
+		if cfg.rr != nil && n > 0 {
+			cfg.br = bufio.NewReaderSize(cfg.rr, n)
+		}
+	}
+}
+
+// WithIOReaders provides several io.Readers to Bodkin Reader, read in order as a single
+// stream of data, ie. the shards of a dataset, along with a delimiter to use to split
+// datum in the data streams.
+// The end of each reader is a datum boundary: a reader whose data doesn't end with the
+// delimiter has its last datum ended there, rather than carried over to the next reader.
+func WithIOReaders(readers []io.Reader, delim byte) Option {
+	return WithIOReader(newMultiReader(readers, delim), delim)
+}
+
+// WithReserve specifies the number of rows reserved in the record builders when a
+// record is started, to avoid growing them repeatedly while loading data. In chunked
+// mode the larger of n and the chunk size is reserved.
+// DataReader.Realloc() reports how often the builders still had to grow.
+func WithReserve(n int) Option {
+	return func(cfg config) {
+		cfg.reserve = n
+	}
+}
+
+// WithLogger routes the reader's internal events, ie. recovered panics, input decode
+// failures and the end of its input and record goroutines, to logger with a level
+// (LogDebug, LogWarn or LogError), a message and key-value pairs.
+// The logger is called from the reader's goroutines and should not block.
+func WithLogger(logger func(level, msg string, kv ...any)) Option {
+	return func(cfg config) {
+		cfg.logger = logger
+	}
+}
+
+// WithJSONStream reads the io.Reader as a stream of concatenated JSON values, ie.
+// {...}{...}, optionally separated by whitespace, instead of splitting it on the
+// delimiter. Values may span lines. A malformed value ends the stream.
+func WithJSONStream() Option {
+	return func(cfg config) {
+		cfg.jsonStream = true
+	}
+}
+
+// WithPersistentDictionaries keeps the dictionaries of dictionary columns, ie. Avro
+// enums, across records and Reset(): values keep the same index in every record, and
+// each record's dictionary holds every value seen to date. By default dictionaries
+// are reset with each record, which then only holds its own values, after the Avro
+// enum symbols which always keep their index.
+func WithPersistentDictionaries() Option {
+	return func(cfg config) {
+		cfg.persistDicts = true
+	}
+}
+
 // WithInputBufferSize specifies the Bodkin Reader's input buffer size.
 func WithInputBufferSize(n int) Option {
 	return func(cfg config) {
@@ -60,3 +136,198 @@ func WithRecordBufferSize(n int) Option {
 		cfg.recordBufferSize = n
 	}
 }
+
+// WithContentHash adds a column named columnName to the Reader's schema, holding a
+// hash of each datum's fields specified as dotpaths, ie. "$id" or "$user.name", or of
+// the whole datum if no fields are specified. The hash can be used downstream to
+// deduplicate rows or detect changes.
+//
+// The hash is the 32-byte SHA-256 digest of the canonical JSON encoding of the
+// values, stored as fixed_size_binary[32]. Object keys are sorted and JSON numbers keep
+// their textual form, so the hash is stable across runs and processes for the same
+// input, but 1 and 1.0 hash differently. For field hashes the values are encoded as a
+// JSON array in the order specified, with absent fields encoded as null.
+// A whole datum hash includes fields that are not in the schema.
+func WithContentHash(columnName string, fields ...string) Option {
+	return func(cfg config) {
+		cfg.contentHash = newContentHash(columnName, fields)
+	}
+}
+
+// WithBoolTokens specifies string or number tokens loaded as true or false into
+// boolean columns, in addition to JSON booleans.
+func WithBoolTokens(trueSet, falseSet []string) Option {
+	return func(cfg config) {
+		cfg.boolTokens = make(map[string]bool, len(trueSet)+len(falseSet))
+		for _, t := range trueSet {
+			cfg.boolTokens[t] = true
+		}
+		for _, t := range falseSet {
+			cfg.boolTokens[t] = false
+		}
+	}
+}
+
+// WithAllowNonFiniteFloats accepts the bare NaN, Infinity and Inf tokens emitted by
+// lenient JSON encoders in input data, loading them as non-finite float values.
+// The same tokens as strings are always loaded as floats into float columns.
+func WithAllowNonFiniteFloats() Option {
+	return func(cfg config) {
+		cfg.allowNonFinite = true
+	}
+}
+
+// WithTimeLayouts specifies Go time layouts used to load strings into date and
+// timestamp columns when they are not in one of the default formats.
+// Layouts are tried in order and the first that parses a value is used.
+func WithTimeLayouts(layouts ...string) Option {
+	return func(cfg config) {
+		cfg.timeLayouts = layouts
+	}
+}
+
+// WithAssumeTimeZone interprets timestamp strings without a UTC offset or time zone,
+// ie. "2024-03-01 12:00:00", as times in loc, storing them as the UTC instants they
+// describe. Strings with an offset keep it. By default they are taken to be in UTC.
+func WithAssumeTimeZone(loc *time.Location) Option {
+	return func(cfg config) {
+		cfg.location = loc
+	}
+}
+
+// WithDuplicateKeyPolicy specifies how a key appearing more than once in the same
+// JSON object is handled, the default is DuplicateKeyLastWins.
+func WithDuplicateKeyPolicy(policy DuplicateKeyPolicy) Option {
+	return func(cfg config) {
+		cfg.dupKeys = policy
+	}
+}
+
+// WithInvalidUTF8Policy specifies how string values which are not valid UTF-8 are
+// loaded into string columns, as invalid strings can't be written to Parquet.
+// The default, InvalidUTF8Replace, replaces invalid bytes with U+FFFD.
+func WithInvalidUTF8Policy(policy InvalidUTF8Policy) Option {
+	return func(cfg config) {
+		cfg.utf8Policy = policy
+	}
+}
+
+// WithTreatBlankAsNull loads string values which are empty or only white space, as
+// defined by IsBlank, as null into columns of any type.
+func WithTreatBlankAsNull() Option {
+	return func(cfg config) {
+		cfg.blankAsNull = true
+	}
+}
+
+// WithDefault loads value into the scalar field at dotpath, ie. "$a.b", when the field
+// is absent or null in a datum, instead of null, ie. to write dense Parquet from sparse
+// JSON. Fields in structs are addressed by their input key. NewReader returns an error
+// wrapping ErrInvalidDefault if the path is not a scalar field or if value can't be
+// loaded as the field's type. Values in lists are not filled.
+func WithDefault(dotpath string, value any) Option {
+	return func(cfg config) {
+		if cfg.defaults == nil {
+			cfg.defaults = make(map[string]any)
+		}
+		cfg.defaults["$"+strings.TrimPrefix(strings.TrimPrefix(dotpath, "$"), ".")] = value
+	}
+}
+
+// WithDatumTransform applies fn to each raw JSON datum before it is decoded, ie. a
+// Bloblang mapping stripping nulls and empty values, avoiding a separate cleaning pass.
+// A datum for which fn returns an error is not loaded and counted as a decode error;
+// the error, wrapping ErrDatumTransform, is reported by Err().
+func WithDatumTransform(fn func([]byte) ([]byte, error)) Option {
+	return func(cfg config) {
+		cfg.datumTransform = fn
+	}
+}
+
+// WithWrapTopLevelValues loads raw JSON datum which are not objects, ie. the strings,
+// numbers or arrays of a simple event log, as objects with the value under the field
+// name, DefaultValueField if name is empty. See WrapTopLevelValue.
+func WithWrapTopLevelValues(name string) Option {
+	return func(cfg config) {
+		if name == "" {
+			name = DefaultValueField
+		}
+		cfg.valueField = name
+	}
+}
+
+// WithMemoryLimit caps the bytes held by the reader's record builders and the records
+// they built which have not been released, so that an enormous datum fails instead of
+// exhausting memory. An allocation which would exceed limit fails the record being
+// built: its rows are discarded, and the reader continues with the next datum. The
+// discarded datum are counted in Metrics().DiscardedRecords, logged at LogWarn with an
+// error wrapping ErrMemoryLimit, and the datum which exceeded the limit is written to
+// the sink set with WithBadRecordSink; they are not reported by Err(). ReadToRecord
+// returns the error.
+func WithMemoryLimit(limit int64) Option {
+	return func(cfg config) {
+		cfg.memLimit = limit
+	}
+}
+
+// WithCaseInsensitiveKeys loads the keys of input objects into the fields whose names
+// they match under case-folding, ie. userid into UserID. If an object has several keys
+// matching a field, the one spelled as the field's name is loaded, or else the
+// smallest. Map keys are left as is.
+func WithCaseInsensitiveKeys() Option {
+	return func(cfg config) {
+		cfg.caseInsensitive = true
+	}
+}
+
+// WithAvroLogicalTypeMap converts the values of Avro logical types loaded from
+// DataSourceAvro input as described by m, ie. timestamp-millis longs into microsecond
+// timestamp columns, instead of loading the numbers as is. See DefaultAvroLogicalTypeMap.
+func WithAvroLogicalTypeMap(m AvroLogicalTypeMap) Option {
+	return func(cfg config) {
+		cfg.avroTypes = &m
+	}
+}
+
+// WithSkipBadRecords skips a datum which fails to load, instead of stopping the reader
+// with the error. Skipped datum are counted in Metrics().SkippedRecords and logged at
+// LogWarn with their error to the logger set with WithLogger; they are not reported by
+// Err(). The rows of the record being built are kept. See WithBadRecordSink to capture
+// skipped datum.
+func WithSkipBadRecords() Option {
+	return func(cfg config) {
+		cfg.skipBad = true
+	}
+}
+
+// WithBadRecordSink writes each datum skipped with WithSkipBadRecords, or exceeding the
+// limit set with WithMemoryLimit, to w as a line of JSON, so that it can be inspected
+// or replayed.
+func WithBadRecordSink(w io.Writer) Option {
+	return func(cfg config) {
+		cfg.badRecordSink = w
+	}
+}
+
+// WithMaxArrayLength truncates arrays of more than n elements to their first n elements
+// when loading them into list columns, so that a bad datum holding millions of elements
+// doesn't produce an enormous row. Truncation is lossy: the dropped elements are not
+// loaded anywhere. Each truncation is counted in Metrics().TruncatedArrays and a datum
+// with truncated arrays is logged at LogWarn to the logger set with WithLogger.
+// Fixed size lists are not truncated, and neither are arrays decoded by WithJSONDecoder.
+func WithMaxArrayLength(n int) Option {
+	return func(cfg config) {
+		cfg.maxArrayLen = n
+	}
+}
+
+// WithColumnOrder orders the top-level columns of the records named in order first,
+// followed by the other columns in schema order, ie. to match a consumer's layout.
+// Columns are named by their field name or input name, with or without the leading "$",
+// and are loaded from the same input keys whatever their order. NewReader returns an
+// error wrapping ErrColumnNotFound if a name is not a column, or is listed twice.
+func WithColumnOrder(order []string) Option {
+	return func(cfg config) {
+		cfg.columnOrder = order
+	}
+}
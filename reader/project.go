@@ -0,0 +1,110 @@
+package reader
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+var (
+	ErrProjectionNotFound = errors.New("projected field not found")
+	ErrColumnNotFound     = errors.New("ordered column not found")
+)
+
+// NewReaderProjected returns a new Reader for the fields of schema listed in project,
+// in the order of the schema. Only the builders of projected fields are created, input
+// data for other fields is never loaded.
+//
+// Projected fields are dotpaths, with or without the leading "$", into nested structs:
+// "$a.b" projects field b of struct a, keeping a with only the fields projected in it.
+// Projecting a list or map projects all of its content.
+func NewReaderProjected(schema *arrow.Schema, source DataSource, project []string, opts ...Option) (*DataReader, error) {
+	paths := make([][]string, 0, len(project))
+	for _, p := range project {
+		paths = append(paths, strings.Split(strings.TrimPrefix(p, "$"), "."))
+	}
+	fields, err := projectFields(schema.Fields(), paths, "$")
+	if err != nil {
+		return nil, err
+	}
+	md := schema.Metadata()
+	return NewReader(arrow.NewSchema(fields, &md), source, opts...)
+}
+
+// projectFields returns the fields selected by paths, relative to fields at prefix.
+func projectFields(fields []arrow.Field, paths [][]string, prefix string) ([]arrow.Field, error) {
+	sub := make(map[string][][]string)
+	whole := make(map[string]bool)
+	for _, p := range paths {
+		if len(p) == 1 {
+			whole[p[0]] = true
+		} else {
+			sub[p[0]] = append(sub[p[0]], p[1:])
+		}
+		if !hasField(fields, p[0]) {
+			return nil, fmt.Errorf("%w : %s", ErrProjectionNotFound, prefix+"."+strings.Join(p, "."))
+		}
+	}
+	var projected []arrow.Field
+	for _, f := range fields {
+		switch {
+		case whole[f.Name]:
+			projected = append(projected, f)
+		case len(sub[f.Name]) > 0:
+			st, ok := f.Type.(*arrow.StructType)
+			if !ok {
+				return nil, fmt.Errorf("%w : %s.%s is not a struct", ErrProjectionNotFound, prefix, f.Name)
+			}
+			children, err := projectFields(st.Fields(), sub[f.Name], prefix+"."+f.Name)
+			if err != nil {
+				return nil, err
+			}
+			f.Type = arrow.StructOf(children...)
+			projected = append(projected, f)
+		}
+	}
+	return projected, nil
+}
+
+func hasField(fields []arrow.Field, name string) bool {
+	for _, f := range fields {
+		if f.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// orderColumns returns schema with the top-level fields named in order first, in that
+// order, followed by the other fields in schema order. Names are field names or input
+// names, with or without the leading "$".
+func orderColumns(schema *arrow.Schema, order []string) (*arrow.Schema, error) {
+	fields := schema.Fields()
+	taken := make([]bool, len(fields))
+	ordered := make([]arrow.Field, 0, len(fields))
+	for _, name := range order {
+		name = strings.TrimPrefix(strings.TrimPrefix(name, "$"), ".")
+		found := false
+		for i, f := range fields {
+			orig, _ := f.Metadata.GetValue(OriginalNameKey)
+			if taken[i] || f.Name != name && orig != name {
+				continue
+			}
+			taken[i], found = true, true
+			ordered = append(ordered, f)
+			break
+		}
+		if !found {
+			return nil, fmt.Errorf("%w : %s", ErrColumnNotFound, name)
+		}
+	}
+	for i, f := range fields {
+		if !taken[i] {
+			ordered = append(ordered, f)
+		}
+	}
+	md := schema.Metadata()
+	return arrow.NewSchema(ordered, &md), nil
+}
@@ -5,11 +5,14 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	stdjson "encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"slices"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
@@ -40,6 +43,7 @@ type DataReader struct {
 	rr               io.Reader
 	br               *bufio.Reader
 	delim            byte
+	scanBufferSize   int
 	refs             int64
 	source           DataSource
 	schema           *arrow.Schema
@@ -60,11 +64,50 @@ type DataReader struct {
 	inputLock        atomic.Int32
 	factoryLock      atomic.Int32
 	wg               sync.WaitGroup
+	running          sync.WaitGroup
+	closed           bool
 	jsonDecode       bool
 	chunk            int
 	inputCount       int
 	inputBufferSize  int
 	recordBufferSize int
+	contentHash      *contentHash
+	boolTokens       map[string]bool
+	memStats         *statsAllocator
+	allowNonFinite   bool
+	timeLayouts      []string
+	location         *time.Location
+	dupKeys          DuplicateKeyPolicy
+	utf8Policy       InvalidUTF8Policy
+	reserve          int
+	reallocs         *reallocCounter
+	logger           func(level, msg string, kv ...any)
+	jsonStream       bool
+	stream           *stdjson.Decoder
+	persistDicts     bool
+	blankAsNull      bool
+	flushInterval    time.Duration
+	defaults         map[string]any
+	counters         readerCounters
+	extra            string
+	fieldNames       map[string]bool
+	explode          []string
+	datumTransform   func([]byte) ([]byte, error)
+	valueField       string
+	flat             *flatLoader
+	memLimit         int64
+	limiter          *limitAllocator
+	columnOrder      []string
+	maxArrayLen      int
+	skipBad          bool
+	avroTypes        *AvroLogicalTypeMap
+	caseInsensitive  bool
+	keyFold          *keyFold
+	badRecordSink    io.Writer
+	// rows of the record being built kept by rollback, emitted with it
+	kept []arrow.Record
+	// datum loaded into the record being built by the record factory
+	building int
 }
 
 func NewReader(schema *arrow.Schema, source DataSource, opts ...Option) (*DataReader, error) {
@@ -84,9 +127,29 @@ func NewReader(schema *arrow.Schema, source DataSource, opts ...Option) (*DataRe
 		delim:            DefaultDelimiter,
 		opts:             opts,
 	}
+	r.counters.started = time.Now()
 	for _, opt := range opts {
 		opt(r)
 	}
+	if err := checkDefaults(schema, r.defaults); err != nil {
+		return nil, err
+	}
+	r.extra, r.fieldNames = extraColumn(schema)
+	if r.caseInsensitive {
+		r.keyFold = newKeyFold(schema.Fields())
+	}
+	if r.contentHash != nil {
+		md := schema.Metadata()
+		schema = arrow.NewSchema(append(slices.Clone(schema.Fields()), r.contentHash.field()), &md)
+		r.schema = schema
+	}
+	if len(r.columnOrder) > 0 {
+		var err error
+		if schema, err = orderColumns(schema, r.columnOrder); err != nil {
+			return nil, err
+		}
+		r.schema = schema
+	}
 
 	r.anyChan = make(chan any, r.inputBufferSize)
 	r.recChan = make(chan arrow.Record, r.recordBufferSize)
@@ -96,38 +159,70 @@ func NewReader(schema *arrow.Schema, source DataSource, opts ...Option) (*DataRe
 
 	if r.rr != nil {
 		r.wg.Add(1)
+		r.running.Add(1)
 		go r.decode2Chan()
 	}
-	r.bld = array.NewRecordBuilder(memory.DefaultAllocator, schema)
+	if ca, ok := r.mem.(*memory.CheckedAllocator); ok {
+		r.memStats = &statsAllocator{CheckedAllocator: ca}
+		r.mem = r.memStats
+	}
+	r.reallocs = &reallocCounter{Allocator: r.mem}
+	r.mem = r.reallocs
+	if r.memLimit > 0 {
+		r.limiter = &limitAllocator{Allocator: r.mem, limit: r.memLimit}
+		r.mem = r.limiter
+	}
+	r.bld = array.NewRecordBuilder(r.mem, schema)
 	r.bldMap = newFieldPos()
 	r.bldMap.isStruct = true
+	r.bldMap.source = source
+	r.bldMap.boolTokens = r.boolTokens
+	r.bldMap.timeLayouts = r.timeLayouts
+	r.bldMap.location = r.location
+	r.bldMap.avroTypes = r.avroTypes
+	if r.maxArrayLen > 0 {
+		r.bldMap.arrayLimit = &arrayLimit{max: r.maxArrayLen, truncated: &r.counters.truncatedArrays}
+	}
+	r.bldMap.utf8Policy = r.utf8Policy
+	r.bldMap.blankAsNull = r.blankAsNull
+	r.bldMap.defaults = r.defaults
 	r.source = source
 	r.ldr = newDataLoader()
 	for idx, fb := range r.bld.Fields() {
 		mapFieldBuilders(fb, schema.Field(idx), r.bldMap)
 	}
 	r.ldr.drawTree(r.bldMap)
-	go r.recordFactory()
+	r.flat = newFlatLoader(schema, r.bldMap)
 	r.wg.Add(1)
+	r.running.Add(1)
+	go r.recordFactory()
 	return r, nil
 }
 
 // ReadToRecord decodes a datum directly to an arrow.Record. The record
-// should be released by the user when done with it.
-func (r *DataReader) ReadToRecord(a any) (arrow.Record, error) {
-	var err error
+// should be released by the user when done with it. If the datum fails to load, its
+// row is discarded and the error returned, leaving the reader ready for the next datum.
+func (r *DataReader) ReadToRecord(a any) (rec arrow.Record, err error) {
 	defer func() {
 		if rc := recover(); rc != nil {
-			fmt.Println(rc, err)
+			r.log(LogError, "panic recovered in ReadToRecord", "panic", rc, "err", err)
+			err = errors.Join(err, fmt.Errorf("panic %v", rc))
+			r.err = errors.Join(r.err, err)
+			rec = nil
 		}
 	}()
-	m, err := InputMap(a)
+	m, err := r.inputMap(a)
+	r.counters.countInput(a, err)
 	if err != nil {
 		r.err = errors.Join(r.err, err)
 	}
 
+	start := r.builtRows()
 	switch r.jsonDecode {
 	case true:
+		if r.contentHash != nil {
+			m = r.contentHash.withHash(m).(map[string]any)
+		}
 		var v []byte
 		v, err = json.Marshal(m)
 		if err != nil {
@@ -138,16 +233,140 @@ func (r *DataReader) ReadToRecord(a any) (arrow.Record, error) {
 		d.UseNumber()
 		err = d.Decode(r.bld)
 		if err != nil {
+			r.rollback(start)
 			return nil, err
 		}
 	default:
-		err = r.ldr.loadDatum(m)
+		err = r.loadDatum(m)
 		if err != nil {
+			if !errors.Is(err, ErrMemoryLimit) {
+				r.rollback(start)
+			}
 			return nil, err
 		}
 	}
 
-	return r.bld.NewRecord(), nil
+	return r.newRecord(), nil
+}
+
+// newRecord returns the record built to date, resetting the dictionaries of dictionary
+// columns unless WithPersistentDictionaries is set.
+func (r *DataReader) newRecord() arrow.Record {
+	rec := r.bld.NewRecord()
+	if len(r.kept) > 0 {
+		rec = r.withKept(rec)
+	}
+	r.building = 0
+	r.counters.records.Add(1)
+	r.counters.rows.Add(rec.NumRows())
+	if !r.persistDicts {
+		r.bldMap.resetDictionaries()
+	}
+	return rec
+}
+
+// builtRows returns the number of rows loaded to the record builder.
+func (r *DataReader) builtRows() int {
+	if r.bld.Schema().NumFields() == 0 {
+		return 0
+	}
+	return r.bld.Field(0).Len()
+}
+
+// rollback discards the rows loaded to the record builder from row start on, ie. those
+// of a datum which failed to load, keeping the rows before it to be emitted with the
+// record being built. A datum which fails to load leaves its row complete, having
+// appended null for the values which failed, so the columns can be sliced at start.
+func (r *DataReader) rollback(start int) {
+	fields := r.bld.Fields()
+	cols := make([]arrow.Array, len(fields))
+	for i, fb := range fields {
+		a := fb.NewArray()
+		if start > 0 {
+			cols[i] = array.NewSlice(a, 0, int64(start))
+		}
+		a.Release()
+	}
+	if start == 0 {
+		return
+	}
+	r.kept = append(r.kept, array.NewRecord(r.bld.Schema(), cols, int64(start)))
+	for _, c := range cols {
+		c.Release()
+	}
+}
+
+// withKept returns rec preceded by the rows kept by rollback, releasing them.
+func (r *DataReader) withKept(rec arrow.Record) arrow.Record {
+	recs := append(r.kept, rec)
+	r.kept = nil
+	merged, err := concatRecords(recs, r.mem)
+	if err != nil {
+		r.err = errors.Join(r.err, fmt.Errorf("merging rolled back record : %w", err))
+		merged = rec
+		recs = recs[:len(recs)-1]
+	}
+	for _, k := range recs {
+		k.Release()
+	}
+	return merged
+}
+
+// releaseKept releases the rows kept by rollback.
+func (r *DataReader) releaseKept() {
+	for _, k := range r.kept {
+		k.Release()
+	}
+	r.kept = nil
+}
+
+// loadDatum loads a decoded datum to the record builder, as one row per element of the
+// lists exploded with Explode. If loading it would exceed the memory limit set with
+// WithMemoryLimit, the record being built is discarded and an error wrapping
+// ErrMemoryLimit is returned.
+func (r *DataReader) loadDatum(data any) (err error) {
+	if r.limiter != nil {
+		r.limiter.enforcing.Store(true)
+		defer func() {
+			if rc := recover(); rc != nil {
+				err = r.limiter.recovered(rc, r.bld)
+				r.releaseKept()
+			}
+			r.limiter.enforcing.Store(false)
+		}()
+	}
+	if len(r.explode) == 0 {
+		return r.loadRow(data)
+	}
+	for _, row := range r.explodeRows(data) {
+		if err := r.loadRow(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadRow loads a row to the record builder, matching its keys to fields regardless
+// of case if WithCaseInsensitiveKeys is set, moving keys which are not fields of the
+// schema into its extra column if it has one, and adding its content hash if
+// WithContentHash is set.
+func (r *DataReader) loadRow(data any) error {
+	if r.keyFold != nil {
+		data = r.keyFold.fold(data)
+	}
+	if r.extra != "" {
+		data = r.withExtra(data)
+	}
+	if r.contentHash != nil {
+		data = r.contentHash.withHash(data)
+	}
+	if m, ok := data.(map[string]any); ok && r.flat != nil {
+		return r.flat.loadDatum(m)
+	}
+	if r.maxArrayLen > 0 {
+		return r.loadTruncated(data)
+	}
+	return r.ldr.loadDatum(data)
 }
 
 // NextBatch returns whether a []arrow.Record of a specified size can be received
@@ -167,6 +386,8 @@ func (r *DataReader) NextBatch(batchSize int) bool {
 	}
 	r.wg.Wait()
 
+	// the record factory signals once that it is done
+	bldDone := r.bldDone
 	for len(r.curBatch) <= batchSize {
 		select {
 		case rec, ok := <-r.recChan:
@@ -179,7 +400,8 @@ func (r *DataReader) NextBatch(batchSize int) bool {
 			if rec != nil {
 				r.curBatch = append(r.curBatch, rec)
 			}
-		case <-r.bldDone:
+		case <-bldDone:
+			bldDone = nil
 			if len(r.recChan) > 0 {
 				rec := <-r.recChan
 				r.curBatch = append(r.curBatch, rec)
@@ -197,6 +419,54 @@ jump:
 	return len(r.curBatch) > 0
 }
 
+// NextMerged is like NextBatch, but returns the batch's records concatenated into a
+// single record, which the caller must release. It returns false if NextBatch does,
+// or if the records could not be concatenated, in which case Err() returns the error.
+func (r *DataReader) NextMerged(batchSize int) (arrow.Record, bool) {
+	if !r.NextBatch(batchSize) {
+		return nil, false
+	}
+	if len(r.curBatch) == 1 {
+		r.curBatch[0].Retain()
+		return r.curBatch[0], true
+	}
+	rec, err := concatRecords(r.curBatch, r.mem)
+	if err != nil {
+		r.err = errors.Join(r.err, fmt.Errorf("nextmerged %w", err))
+		return nil, false
+	}
+	return rec, true
+}
+
+// concatRecords returns recs, which share a schema, concatenated into a single record.
+func concatRecords(recs []arrow.Record, mem memory.Allocator) (arrow.Record, error) {
+	var rows int64
+	for _, rec := range recs {
+		rows += rec.NumRows()
+	}
+	sc := recs[0].Schema()
+	cols := make([]arrow.Array, sc.NumFields())
+	defer func() {
+		for _, c := range cols {
+			if c != nil {
+				c.Release()
+			}
+		}
+	}()
+	arrs := make([]arrow.Array, len(recs))
+	for i := range cols {
+		for j, rec := range recs {
+			arrs[j] = rec.Column(i)
+		}
+		c, err := array.Concatenate(arrs, mem)
+		if err != nil {
+			return nil, fmt.Errorf("%s : %w", sc.Field(i).Name, err)
+		}
+		cols[i] = c
+	}
+	return array.NewRecord(sc, cols, rows), nil
+}
+
 // Next returns whether a Record can be received from the converted record queue.
 // The user should check Err() after a call to Next that returned false to check
 // if an error took place.
@@ -278,7 +548,8 @@ func (r *DataReader) Peek() (int, int) {
 	return len(r.anyChan), len(r.recChan)
 }
 
-// Cancel cancels the Reader's io.Reader scan to Arrow.
+// Cancel cancels the Reader's io.Reader scan to Arrow. Use Close to also wait for
+// the Reader's goroutines to exit.
 func (r *DataReader) Cancel() {
 	r.readCancel()
 }
@@ -293,12 +564,15 @@ func (r *DataReader) Read(a any) error {
 	defer func() error {
 		if rc := recover(); rc != nil {
 			r.err = errors.Join(r.err, fmt.Errorf("panic %v", rc))
+			r.log(LogError, "panic recovered in Read", "panic", rc)
 		}
 		return r.err
 	}()
-	m, err := InputMap(a)
+	m, err := r.inputMap(a)
+	r.counters.countInput(a, err)
 	if err != nil {
 		r.err = errors.Join(r.err, err)
+		r.log(LogWarn, "input decode failed", "err", err)
 		return err
 	}
 	r.anyChan <- m
@@ -306,9 +580,14 @@ func (r *DataReader) Read(a any) error {
 	return nil
 }
 
-// Reset resets a Reader to its initial state.
+// Reset resets a Reader to its initial state, stopping its goroutines and discarding
+// buffered data and records as Close does.
 func (r *DataReader) Reset() {
-	r.readCancel()
+	r.stop()
+	// discard rows loaded but not emitted
+	r.bld.NewRecord().Release()
+	r.releaseKept()
+	r.readerCtx, r.readCancel = context.WithCancel(context.Background())
 	r.anyChan = make(chan any, r.inputBufferSize)
 	r.recChan = make(chan arrow.Record, r.recordBufferSize)
 	r.bldDone = make(chan struct{})
@@ -317,9 +596,103 @@ func (r *DataReader) Reset() {
 	// DataReader has an io.Reader
 	if r.rr != nil {
 		r.br.Reset(r.rr)
-		go r.decode2Chan()
+		r.stream = nil
 		r.wg.Add(1)
+		r.running.Add(1)
+		go r.decode2Chan()
 	}
-	go r.recordFactory()
 	r.wg.Add(1)
+	r.running.Add(1)
+	go r.recordFactory()
+}
+
+// Close stops the Reader: its scan of the io.Reader is cancelled, its goroutines are
+// waited for, and the records left buffered are released along with the current record
+// and record batch. It returns the errors encountered while reading, as Err does.
+// A read blocked on the io.Reader must return before Close can, ie. close the
+// io.Reader first if it may block indefinitely.
+// The Reader can't be used after Close, which must not be called concurrently with
+// its other methods.
+func (r *DataReader) Close() error {
+	if r.closed {
+		return r.err
+	}
+	r.closed = true
+	r.stop()
+	if r.cur != nil {
+		r.cur.Release()
+		r.cur = nil
+	}
+	for _, rec := range r.curBatch {
+		rec.Release()
+	}
+	r.curBatch = nil
+	r.releaseKept()
+	r.bld.Release()
+	return r.err
+}
+
+// stop cancels the Reader's goroutines and waits for them to exit, releasing the
+// records and dropping the data they leave buffered.
+func (r *DataReader) stop() {
+	r.readCancel()
+	exited := make(chan struct{})
+	go func() {
+		r.running.Wait()
+		close(exited)
+	}()
+	anyChan, recChan, bldDone := r.anyChan, r.recChan, r.bldDone
+	for {
+		select {
+		case _, ok := <-anyChan:
+			if !ok {
+				anyChan = nil
+			}
+		case rec, ok := <-recChan:
+			if !ok {
+				recChan = nil
+				continue
+			}
+			if rec != nil {
+				rec.Release()
+			}
+		case <-bldDone:
+			// the record factory signals once that it is done
+			bldDone = nil
+		case <-exited:
+			for len(recChan) > 0 {
+				if rec := <-recChan; rec != nil {
+					rec.Release()
+				}
+			}
+			return
+		}
+	}
+}
+
+// prepareInput applies the input transformations set by options to raw JSON input.
+func (r *DataReader) prepareInput(a any) (any, error) {
+	if r.allowNonFinite {
+		a = quoteNonFinite(a)
+	}
+	if r.datumTransform != nil {
+		var err error
+		if a, err = TransformDatum(r.datumTransform, a); err != nil {
+			return nil, err
+		}
+	}
+	if r.valueField != "" {
+		a = WrapTopLevelValue(a, r.valueField)
+	}
+	return a, nil
+}
+
+// inputMap decodes input a to a map after applying the input transformations set
+// by options.
+func (r *DataReader) inputMap(a any) (map[string]any, error) {
+	a, err := r.prepareInput(a)
+	if err != nil {
+		return nil, err
+	}
+	return InputMapWithPolicy(a, r.dupKeys)
 }
@@ -1,12 +1,15 @@
 package reader
 
 import (
+	stdjson "encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"time"
 )
 
 func (r *DataReader) decode2Chan() {
+	defer r.running.Done()
 	// 1 means running
 	if r.inputLock.CompareAndSwap(0, 1) {
 		defer r.inputLock.Store(0)
@@ -17,26 +20,44 @@ func (r *DataReader) decode2Chan() {
 	defer func() {
 		if rc := recover(); rc != nil {
 			r.err = errors.Join(r.err, err, fmt.Errorf("panic %v", rc))
+			r.log(LogError, "panic recovered in input decoder", "panic", rc, "err", err)
 		}
 	}()
 	defer close(r.anyChan)
+	defer func() { r.log(LogDebug, "input closed", "count", r.inputCount) }()
 	b := true
+	defer func() {
+		if b {
+			// no datum was decoded
+			r.wg.Done()
+		}
+	}()
 	for {
-		datumBytes, err := r.br.ReadBytes(r.delim)
+		datumBytes, err := r.readDatum()
 		if err != nil {
 			if errors.Is(err, io.EOF) {
 				r.err = nil
 				return
 			}
 			r.err = err
+			r.log(LogError, "input read failed", "err", err)
 			return
 		}
-		datum, err := InputMap(datumBytes[:len(datumBytes)-1])
+		if !r.jsonStream {
+			datumBytes = datumBytes[:len(datumBytes)-1]
+		}
+		datum, err := r.inputMap(datumBytes)
+		r.counters.countInput(datumBytes, err)
 		if err != nil {
 			r.err = errors.Join(r.err, err)
+			r.log(LogWarn, "input decode failed", "err", err)
 			continue
 		}
-		r.anyChan <- datum
+		select {
+		case r.anyChan <- datum:
+		case <-r.readerCtx.Done():
+			return
+		}
 		r.inputCount++
 		if b {
 			r.wg.Done() // sync.WaitGroup to allow Next() to wait for records to be available
@@ -50,24 +71,67 @@ func (r *DataReader) decode2Chan() {
 	}
 }
 
+// readDatum returns the next datum from the io.Reader, ending with the delimiter or,
+// with WithJSONStream, the next top-level JSON value.
+func (r *DataReader) readDatum() ([]byte, error) {
+	if !r.jsonStream {
+		return r.br.ReadBytes(r.delim)
+	}
+	if r.stream == nil {
+		r.stream = stdjson.NewDecoder(r.br)
+	}
+	var raw stdjson.RawMessage
+	if err := r.stream.Decode(&raw); err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
+
 // recordFactory... the hits just keep on coming
 func (r *DataReader) recordFactory() {
+	defer r.running.Done()
 	if r.factoryLock.CompareAndSwap(0, 1) {
 		defer r.factoryLock.Store(0)
 	} else {
 		return
 	}
 	defer close(r.recChan)
+	defer r.log(LogDebug, "record factory done")
 	recChunk := 0
+	anyChan := r.anyChan
 
 	r.wg.Done() // sync.WaitGroup to allow Next() to wait for records to be available
 
 	switch {
 	case r.chunk < 1:
-		for data := range r.anyChan {
-			err := r.ldr.loadDatum(data)
+		if r.reserve > 0 {
+			r.bld.Reserve(r.reserve)
+		}
+	manual:
+		for {
+			var data any
+			select {
+			case d, ok := <-anyChan:
+				if !ok {
+					break manual
+				}
+				data = d
+			case <-r.readerCtx.Done():
+				r.bldDone <- struct{}{}
+				return
+			}
+			err := r.loadOrSkip(data)
+			if errors.Is(err, errDatumSkipped) {
+				continue
+			}
+			if errors.Is(err, ErrMemoryLimit) {
+				// counted and logged by loadOrSkip, the reader continues
+				continue
+			}
 			if err != nil {
 				r.err = err
+				r.counters.loadErrors.Add(1)
+				r.log(LogError, "datum load failed, record factory stopped", "err", err)
 				return
 			}
 			select {
@@ -75,36 +139,70 @@ func (r *DataReader) recordFactory() {
 				r.bldDone <- struct{}{}
 				return
 			case <-r.recReq:
-				r.recChan <- r.bld.NewRecord()
+				r.recChan <- r.newRecord()
+				if r.reserve > 0 {
+					r.bld.Reserve(r.reserve)
+				}
 			default:
 			}
 		}
-		r.recChan <- r.bld.NewRecord()
+		r.recChan <- r.newRecord()
 		r.bldDone <- struct{}{}
 	case r.chunk >= 1:
-		for data := range r.anyChan {
-			if recChunk == 0 {
-				r.bld.Reserve(r.chunk)
-			}
-			err := r.ldr.loadDatum(data)
-			if err != nil {
-				r.err = err
-				return
-			}
-			recChunk++
-			if recChunk >= r.chunk {
-				r.recChan <- r.bld.NewRecord()
-				recChunk = 0
-			}
+		// a nil channel unless WithFlushInterval is set
+		var flush <-chan time.Time
+		if r.flushInterval > 0 {
+			t := time.NewTicker(r.flushInterval)
+			defer t.Stop()
+			flush = t.C
+		}
+		idle := true
+	loop:
+		for {
 			select {
+			case data, ok := <-anyChan:
+				if !ok {
+					break loop
+				}
+				if recChunk == 0 {
+					r.bld.Reserve(max(r.chunk, r.reserve))
+				}
+				err := r.loadOrSkip(data)
+				if errors.Is(err, errDatumSkipped) {
+					continue
+				}
+				if errors.Is(err, ErrMemoryLimit) {
+					// counted and logged by loadOrSkip, the reader continues
+					recChunk = 0
+					continue
+				}
+				if err != nil {
+					r.err = err
+					r.counters.loadErrors.Add(1)
+					r.log(LogError, "datum load failed, record factory stopped", "err", err)
+					return
+				}
+				recChunk++
+				idle = false
+				if recChunk >= r.chunk {
+					r.recChan <- r.newRecord()
+					recChunk = 0
+				}
+			case <-flush:
+				// emit the partial record if no data arrived during the last interval
+				if idle && recChunk > 0 {
+					r.log(LogDebug, "flushing partial record", "rows", recChunk)
+					r.recChan <- r.newRecord()
+					recChunk = 0
+				}
+				idle = true
 			case <-r.readerCtx.Done():
 				r.bldDone <- struct{}{}
 				return
-			default:
 			}
 		}
 		if recChunk != 0 {
-			r.recChan <- r.bld.NewRecord()
+			r.recChan <- r.newRecord()
 		}
 		r.bldDone <- struct{}{}
 	}
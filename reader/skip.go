@@ -0,0 +1,45 @@
+package reader
+
+import "errors"
+
+// errDatumSkipped is returned by loadOrSkip for a datum which could not be loaded and
+// was skipped.
+var errDatumSkipped = errors.New("datum skipped")
+
+// loadOrSkip loads data to the record builder. With WithSkipBadRecords, a datum which
+// fails to load is counted, logged and written to the sink set with WithBadRecordSink,
+// and its rows are rolled back, keeping the rows loaded before it; errDatumSkipped is
+// then returned. A datum exceeding the limit set with WithMemoryLimit is counted, logged
+// and written to the sink along with the record being built, which was discarded.
+func (r *DataReader) loadOrSkip(data any) error {
+	start := r.builtRows()
+	err := r.loadDatum(data)
+	switch {
+	case err == nil:
+		r.building++
+		return nil
+	case errors.Is(err, ErrMemoryLimit):
+		r.counters.discarded.Add(int64(r.building) + 1)
+		r.log(LogWarn, "memory limit exceeded, record discarded", "err", err, "datum", r.building+1)
+		r.building = 0
+		r.writeBadRecord(data)
+		return err
+	case !r.skipBad:
+		return err
+	}
+	r.rollback(start)
+	r.counters.skipped.Add(1)
+	r.log(LogWarn, "datum load failed, datum skipped", "err", err)
+	r.writeBadRecord(data)
+	return errDatumSkipped
+}
+
+// writeBadRecord writes data to the sink set with WithBadRecordSink as a line of JSON.
+func (r *DataReader) writeBadRecord(data any) {
+	if r.badRecordSink == nil {
+		return
+	}
+	if _, err := r.badRecordSink.Write(append([]byte(jsonString(data)), '\n')); err != nil {
+		r.log(LogError, "bad record sink write failed", "err", err)
+	}
+}
@@ -0,0 +1,66 @@
+package reader
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+)
+
+func TestSkipBadRecords(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "n", Type: arrow.PrimitiveTypes.Int8, Nullable: true},
+		{Name: "l", Type: arrow.ListOf(arrow.BinaryTypes.String), Nullable: true},
+		{Name: "s", Type: arrow.StructOf(arrow.Field{Name: "x", Type: arrow.PrimitiveTypes.Int8, Nullable: true}), Nullable: true},
+	}, nil)
+	// every third datum overflows int8, in the top-level column or the struct
+	var input strings.Builder
+	var want []string
+	for i := range 30 {
+		switch i % 3 {
+		case 0:
+			fmt.Fprintf(&input, "{\"n\":%d,\"l\":[\"a\",\"b\"],\"s\":{\"x\":1}}\n", 1000+i)
+		case 1:
+			fmt.Fprintf(&input, "{\"n\":%d,\"l\":[\"c\"],\"s\":{\"x\":%d}}\n", i, 1000+i)
+		default:
+			fmt.Fprintf(&input, "{\"n\":%d,\"l\":[\"%d\"],\"s\":{\"x\":%d}}\n", i, i, i)
+			want = append(want, fmt.Sprint(i))
+		}
+	}
+	var sink bytes.Buffer
+	r, err := NewReader(schema, DataSourceJSON, WithChunk(8), WithSkipBadRecords(),
+		WithBadRecordSink(&sink), WithIOReader(strings.NewReader(input.String()), '\n'))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	var got []string
+	for r.Next() {
+		rec := r.Record()
+		l := rec.Column(1).(*array.List)
+		s := rec.Column(2).(*array.Struct)
+		for i := 0; i < int(rec.NumRows()); i++ {
+			n := rec.Column(0).ValueStr(i)
+			start, end := l.ValueOffsets(i)
+			if end-start != 1 || l.ListValues().ValueStr(int(start)) != n || s.Field(0).ValueStr(i) != n {
+				t.Errorf("row %d = n %s, l %s, s %s", i, n, l.ValueStr(i), s.ValueStr(i))
+			}
+			got = append(got, n)
+		}
+	}
+	if r.Err() != nil {
+		t.Fatal(r.Err())
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("rows = %v, want %v", got, want)
+	}
+	if m := r.Metrics(); m.SkippedRecords != 20 {
+		t.Errorf("SkippedRecords = %d, want 20", m.SkippedRecords)
+	}
+	if n := strings.Count(sink.String(), "\n"); n != 20 {
+		t.Errorf("bad record sink has %d lines, want 20", n)
+	}
+}
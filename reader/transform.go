@@ -0,0 +1,29 @@
+package reader
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrDatumTransform = errors.New("datum transform failed")
+
+// TransformDatum applies fn to raw JSON input a, a []byte or string, ie. a Bloblang
+// mapping stripping nulls. Other input is returned as is. The error returned by fn
+// is wrapped in ErrDatumTransform.
+func TransformDatum(fn func([]byte) ([]byte, error), a any) (any, error) {
+	switch t := a.(type) {
+	case []byte:
+		out, err := fn(t)
+		if err != nil {
+			return nil, fmt.Errorf("%w : %w", ErrDatumTransform, err)
+		}
+		return out, nil
+	case string:
+		out, err := fn([]byte(t))
+		if err != nil {
+			return nil, fmt.Errorf("%w : %w", ErrDatumTransform, err)
+		}
+		return string(out), nil
+	}
+	return a, nil
+}
@@ -0,0 +1,31 @@
+package reader
+
+import "sync/atomic"
+
+// arrayLimit is the maximum length of arrays loaded into list columns set with
+// WithMaxArrayLength, shared by the field builders of a reader.
+type arrayLimit struct {
+	max       int
+	truncated *atomic.Int64
+}
+
+// truncate returns the first elements of array s up to the length set with
+// WithMaxArrayLength, counting the truncation if it was longer.
+func (f *fieldPos) truncate(s []any) []any {
+	if f.arrayLimit == nil || f.fixedLen > 0 || len(s) <= f.arrayLimit.max {
+		return s
+	}
+	f.arrayLimit.truncated.Add(1)
+	return s[:f.arrayLimit.max]
+}
+
+// loadTruncated loads data with the data loader, logging a warning if arrays longer than
+// the length set with WithMaxArrayLength were truncated.
+func (r *DataReader) loadTruncated(data any) error {
+	before := r.counters.truncatedArrays.Load()
+	err := r.ldr.loadDatum(data)
+	if n := r.counters.truncatedArrays.Load() - before; n > 0 {
+		r.log(LogWarn, "arrays truncated", "count", n, "max_length", r.maxArrayLen)
+	}
+	return err
+}
@@ -0,0 +1,38 @@
+package reader
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+var ErrInvalidUTF8 = errors.New("invalid utf-8")
+
+// InvalidUTF8Policy specifies how string values which are not valid UTF-8 are loaded
+// into string columns.
+type InvalidUTF8Policy int
+
+const (
+	// InvalidUTF8Replace replaces each run of invalid bytes with U+FFFD, the default.
+	InvalidUTF8Replace InvalidUTF8Policy = iota
+	// InvalidUTF8Drop loads the value as null.
+	InvalidUTF8Drop
+	// InvalidUTF8Error fails the datum with an error wrapping ErrInvalidUTF8.
+	InvalidUTF8Error
+)
+
+// checkUTF8 applies policy to s, returning the string to append or null if the value
+// should be appended as null.
+func checkUTF8(s string, policy InvalidUTF8Policy) (v string, null bool, err error) {
+	if utf8.ValidString(s) {
+		return s, false, nil
+	}
+	switch policy {
+	case InvalidUTF8Drop:
+		return "", true, nil
+	case InvalidUTF8Error:
+		return "", false, fmt.Errorf("%w : %q", ErrInvalidUTF8, s)
+	}
+	return strings.ToValidUTF8(s, "\uFFFD"), false, nil
+}
@@ -0,0 +1,119 @@
+package reader
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+)
+
+func FuzzInvalidUTF8(f *testing.F) {
+	for _, seed := range []string{
+		`{"s":"ok","n":1}`,
+		"{\"s\":\"\xff\xfe\",\"l\":[\"a\xc3\",\"\xe2\x82\"],\"st\":{\"x\":\"\xed\xa0\x80\"}}",
+		`{"s":"\ud800","l":["\udfff"]}`,
+		"{\"s\xff\":\"v\"}",
+		`{"s":`,
+		``,
+	} {
+		f.Add([]byte(seed))
+	}
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "s", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "n", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+		{Name: "l", Type: arrow.ListOf(arrow.BinaryTypes.String), Nullable: true},
+		{Name: "st", Type: arrow.StructOf(arrow.Field{Name: "x", Type: arrow.BinaryTypes.String, Nullable: true}), Nullable: true},
+	}, nil)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if _, err := InputMap(data); err != nil {
+			return
+		}
+		r, err := NewReader(schema, DataSourceJSON, WithInvalidUTF8Policy(InvalidUTF8Replace))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer r.Release()
+		rec, err := r.ReadToRecord(data)
+		if err != nil {
+			return
+		}
+		defer rec.Release()
+		for i, col := range rec.Columns() {
+			if s, ok := invalidString(col); ok {
+				t.Errorf("column %s holds invalid UTF-8 %q", rec.ColumnName(i), s)
+			}
+		}
+	})
+}
+
+// invalidString returns the first string of a, or of its nested arrays, which is not
+// valid UTF-8.
+func invalidString(a arrow.Array) (string, bool) {
+	switch a := a.(type) {
+	case *array.String:
+		for i := 0; i < a.Len(); i++ {
+			if a.IsValid(i) && !utf8.ValidString(a.Value(i)) {
+				return a.Value(i), true
+			}
+		}
+	case *array.List:
+		return invalidString(a.ListValues())
+	case *array.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			if s, ok := invalidString(a.Field(i)); ok {
+				return s, true
+			}
+		}
+	}
+	return "", false
+}
+
+func TestInvalidUTF8ErrorKeepsRows(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "s", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "n", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+	}, nil)
+	bad := "{\"s\":\"\xff\",\"n\":2}"
+
+	r, err := NewReader(schema, DataSourceJSON, WithInvalidUTF8Policy(InvalidUTF8Error))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.ReadToRecord([]byte(bad)); !errors.Is(err, ErrInvalidUTF8) {
+		t.Fatalf("ReadToRecord() error = %v, want ErrInvalidUTF8", err)
+	}
+	rec, err := r.ReadToRecord([]byte(`{"s":"ok","n":3}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.NumRows() != 1 || rec.Column(0).ValueStr(0) != "ok" || rec.Column(1).ValueStr(0) != "3" {
+		t.Errorf("ReadToRecord() after a rejected datum = %v", rec)
+	}
+	rec.Release()
+	r.Close()
+
+	input := `{"s":"a","n":1}` + "\n" + bad + "\n" + `{"s":"c","n":3}` + "\n"
+	r, err = NewReader(schema, DataSourceJSON, WithInvalidUTF8Policy(InvalidUTF8Error),
+		WithSkipBadRecords(), WithIOReader(strings.NewReader(input), '\n'))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	var s, n []string
+	for r.Next() {
+		rec := r.Record()
+		for i := 0; i < int(rec.NumRows()); i++ {
+			s = append(s, rec.Column(0).ValueStr(i))
+			n = append(n, rec.Column(1).ValueStr(i))
+		}
+	}
+	if r.Err() != nil {
+		t.Fatal(r.Err())
+	}
+	if strings.Join(s, ",") != "a,c" || strings.Join(n, ",") != "1,3" {
+		t.Errorf("rows s = %v, n = %v, want s = [a c], n = [1 3]", s, n)
+	}
+}
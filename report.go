@@ -0,0 +1,57 @@
+package bodkin
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+// SchemaReport returns a human-readable tree of the fields evaluated to date, one per
+// line with its dotpath and Arrow type. Fields that could not be evaluated yet are
+// listed under their parent, marked as pending with the reason.
+func (u *Bodkin) SchemaReport() string {
+	if u.old == nil {
+		return "bodkin not initialised\n"
+	}
+	pending := make(map[string][]*fieldPos)
+	for pair := u.untypedFields.Oldest(); pair != nil; pair = pair.Next() {
+		f := pair.Value
+		if len(f.path) == 0 {
+			continue
+		}
+		parent := "$" + strings.Join(f.path[:len(f.path)-1], ".")
+		pending[parent] = append(pending[parent], f)
+	}
+	var sb strings.Builder
+	u.old.report(&sb, pending, 0)
+	return sb.String()
+}
+
+// report writes the report lines of f's children and pending fields to sb.
+func (f *fieldPos) report(sb *strings.Builder, pending map[string][]*fieldPos, indent int) {
+	pad := strings.Repeat("  ", indent)
+	for _, c := range f.children {
+		t := c.field.Type.String()
+		if arrow.IsNested(c.field.Type.ID()) {
+			t = c.field.Type.Name()
+		}
+		fmt.Fprintf(sb, "%s%s : %s\n", pad, c.dotPath(), t)
+		c.report(sb, pending, indent+1)
+	}
+	for _, p := range pending[f.dotPath()] {
+		fmt.Fprintf(sb, "%s%s : ? [pending: %v]\n", pad, p.dotPath(), pendingReason(p))
+	}
+}
+
+// pendingReason returns why an untyped field could not be evaluated.
+func pendingReason(f *fieldPos) PendingReason {
+	switch f.arrowType {
+	case arrow.STRUCT:
+		return EmptyObject
+	case arrow.LIST:
+		return EmptyArray
+	default:
+		return NullField
+	}
+}
@@ -3,11 +3,17 @@ package bodkin
 import (
 	"errors"
 	"fmt"
+	"maps"
 	"regexp"
 	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
 
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/loicalleyne/bodkin/reader"
 )
 
 type fieldPos struct {
@@ -30,6 +36,18 @@ type fieldPos struct {
 	metadatas    arrow.Metadata
 	index, depth int32
 	err          error
+	// observed integer value range, used by WithExactIntegerWidth
+	intSeen        bool
+	intMin, intMax int64
+	// allocated from fieldPosPool
+	pooled bool
+	// types held by the field, oldest first, once its type changed
+	history []arrow.Type
+	// string holding the JSON text of values of varying structure
+	jsonBlob bool
+	// keys of the object being evaluated which are valid names as is, while renames or
+	// a name sanitizer are set
+	keptNames map[string]bool
 }
 
 // Schema evaluation/evolution errors.
@@ -43,8 +61,52 @@ var (
 	ErrPathNotFound              = errors.New("path not found")
 	ErrFieldTypeChanged          = errors.New("changed")
 	ErrFieldAdded                = errors.New("added")
+	ErrFieldResolved             = errors.New("resolved")
+	ErrFieldTypeConflict         = errors.New("type conflict")
+	ErrPathConflict              = errors.New("path element is not a struct")
+	ErrFieldMaxDepth             = errors.New("max depth exceeded")
+	ErrSchemaEvolved             = errors.New("schema changed after the first record")
+	ErrSchemaFrozen              = errors.New("schema is frozen")
+	ErrFieldNameCollision        = errors.New("case-insensitive name collision")
+	ErrFieldDiverted             = errors.New("diverted")
+	ErrFieldCoerced              = errors.New("coerced")
+	ErrFieldTypeKept             = errors.New("type change skipped")
 )
 
+// SchemaBuildError is returned by Schema, OriginSchema and LastSchema when the schema
+// could not be built, with the dotpath of the field being built when it failed, if it
+// is known, and the value recovered from the panic.
+type SchemaBuildError struct {
+	Dotpath string
+	Value   any
+}
+
+func (e *SchemaBuildError) Error() string {
+	if e.Dotpath == "" {
+		return fmt.Sprintf("schema problem: %v", e.Value)
+	}
+	return fmt.Sprintf("schema problem at %s: %v", e.Dotpath, e.Value)
+}
+
+// Unwrap returns the recovered value if it is an error.
+func (e *SchemaBuildError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// recoverSchema recovers a panic while building a schema into a *SchemaBuildError set
+// to *err, with the dotpath of the field the owner was building, and clears it.
+func (u *Bodkin) recoverSchema(s **arrow.Schema, err *error) {
+	if pErr := recover(); pErr != nil {
+		e := &SchemaBuildError{Value: pErr}
+		if u.building != nil {
+			e.Dotpath = u.building.dotPath()
+		}
+		*s, *err = nil, e
+	}
+	u.building = nil
+}
+
 // UpgradableTypes are scalar types that can be upgraded to a more flexible type.
 var UpgradableTypes []arrow.Type = []arrow.Type{arrow.INT8,
 	arrow.UINT8,
@@ -59,6 +121,8 @@ var UpgradableTypes []arrow.Type = []arrow.Type{arrow.INT8,
 	arrow.DATE32,
 	arrow.TIME64,
 	arrow.TIMESTAMP,
+	arrow.DECIMAL128,
+	arrow.DURATION,
 }
 
 // Regular expressions and variables for type inference.
@@ -102,13 +166,91 @@ func newFieldPos(b *Bodkin) *fieldPos {
 	return f
 }
 
+// fieldPosPool recycles the nodes of the throwaway trees built for each input merged
+// by Unify. Pooled nodes must never be retained in the unified or original trees, or
+// in the owner's field maps.
+var fieldPosPool = sync.Pool{New: func() any { return new(fieldPos) }}
+
+// newPooledFieldPos returns a root fieldPos whose tree is allocated from fieldPosPool,
+// to be released when it is no longer needed.
+func newPooledFieldPos(b *Bodkin) *fieldPos {
+	f := getFieldPos()
+	f.owner = b
+	f.index = -1
+	f.root = f
+	return f
+}
+
+// getFieldPos returns a reset fieldPos from fieldPosPool, reusing its children
+// slice and childmap.
+func getFieldPos() *fieldPos {
+	f := fieldPosPool.Get().(*fieldPos)
+	children, childmap := f.children[:0], f.childmap
+	clear(childmap)
+	if childmap == nil {
+		childmap = make(map[string]*fieldPos)
+	}
+	*f = fieldPos{children: children, childmap: childmap, pooled: true}
+	return f
+}
+
+// release returns f and its descendants to fieldPosPool if they were allocated from it.
+func (f *fieldPos) release() {
+	if f == nil || !f.pooled {
+		return
+	}
+	for _, c := range f.children {
+		c.release()
+	}
+	clear(f.children)
+	fieldPosPool.Put(f)
+}
+
 func (f *fieldPos) assignChild(child *fieldPos) {
+	if f.owner.caseCollisionCheck {
+		f.checkCollision(child)
+	}
 	f.children = append(f.children, child)
 	f.childmap[child.name] = child
-	f.owner.knownFields.Set(child.dotPath(), child)
+	if !child.pooled {
+		f.owner.knownFields.Set(child.dotPath(), child)
+	}
 	f.owner.untypedFields.Delete(child.dotPath())
 }
 
+// checkCollision records a conflict if child's column name is equal to that of one of
+// f's children under Unicode case-folding, ie. Name and name.
+func (f *fieldPos) checkCollision(child *fieldPos) {
+	if child.field.Type == nil {
+		return
+	}
+	for _, c := range f.children {
+		if c.name == child.name || !strings.EqualFold(c.field.Name, child.field.Name) {
+			continue
+		}
+		f.owner.conflicts.Set(child.dotPath(), Field{
+			Dotpath:  child.dotPath(),
+			Type:     child.field.Type.ID(),
+			dataType: child.field.Type,
+			Issue:    fmt.Errorf("%w %v : with %v", ErrFieldNameCollision, child.dotPath(), c.dotPath()),
+		})
+		return
+	}
+}
+
+// setUntyped records f as a field whose type could not be evaluated yet. A detached
+// copy of pooled nodes is recorded.
+func (f *fieldPos) setUntyped() {
+	if f.pooled {
+		d := *f
+		d.pooled = false
+		d.root, d.parent = nil, nil
+		d.children, d.childmap = nil, nil
+		f = &d
+	}
+	f.owner.untypedFields.Set(f.dotPath(), f)
+}
+
 func (f *fieldPos) child(index int) (*fieldPos, error) {
 	if index < len(f.children) {
 		return f.children[index], nil
@@ -120,21 +262,106 @@ func (f *fieldPos) error() error             { return f.err }
 func (f *fieldPos) metadata() arrow.Metadata { return f.field.Metadata }
 
 func (f *fieldPos) newChild(childName string) *fieldPos {
-	var child fieldPos = fieldPos{
-		root:   f.root,
-		parent: f,
-		owner:  f.owner,
-		name:   childName,
-		index:  int32(len(f.children)),
-		depth:  f.depth + 1,
+	var child *fieldPos
+	if f.pooled {
+		child = getFieldPos()
+	} else {
+		child = &fieldPos{childmap: make(map[string]*fieldPos)}
 	}
+	child.root = f.root
+	child.parent = f
+	child.owner = f.owner
+	child.name = childName
+	child.index = int32(len(f.children))
+	child.depth = f.depth + 1
 	if f.isList {
 		child.isItem = true
 	}
 	child.path = child.namePath()
-	child.childmap = make(map[string]*fieldPos)
 	child.arrowType = arrow.NULL
-	return &child
+	return child
+}
+
+// fieldName returns the Arrow field name to use for the child key k, along with
+// metadata preserving the original key if the name was changed by the owner's
+// renames or name sanitizer. Names colliding with an existing sibling field, or with
+// a sibling key of the object being evaluated which is a valid name as is, are
+// disambiguated with a numeric suffix.
+func (f *fieldPos) fieldName(k string) (string, arrow.Metadata) {
+	safe, renamed := f.renamed(k)
+	if !renamed {
+		return k, arrow.Metadata{}
+	}
+	taken := func(name string) bool {
+		return f.nameTaken(name, k) || (name != k && f.keptNames[name])
+	}
+	name := safe
+	for i := 2; taken(name); i++ {
+		name = safe + "_" + strconv.Itoa(i)
+	}
+	if name == k {
+		return k, arrow.Metadata{}
+	}
+	return name, arrow.NewMetadata([]string{reader.OriginalNameKey}, []string{k})
+}
+
+// renamed returns the name given to the child key k by the owner's renames or name
+// sanitizer, and whether either applies.
+func (f *fieldPos) renamed(k string) (string, bool) {
+	if safe, ok := f.owner.renames[f.childPath(k)]; ok {
+		return safe, true
+	}
+	if f.owner.nameSanitizer == nil {
+		return k, false
+	}
+	return f.owner.nameSanitizer(k), true
+}
+
+// nameTaken reports whether name is the Arrow name of a child of f other than the
+// child of key k.
+func (f *fieldPos) nameTaken(name, k string) bool {
+	for _, c := range f.children {
+		if c.name != k && c.field.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// keepNames records the keys of m which are valid names as is, so that keys renamed
+// to one of them are suffixed rather than the key itself.
+func (f *fieldPos) keepNames(m map[string]any) {
+	f.keptNames = nil
+	if f.owner.nameSanitizer == nil && len(f.owner.renames) == 0 {
+		return
+	}
+	for k := range m {
+		if name, _ := f.renamed(k); name == k {
+			if f.keptNames == nil {
+				f.keptNames = make(map[string]bool)
+			}
+			f.keptNames[k] = true
+		}
+	}
+}
+
+// DefaultNameSanitizer replaces every character that is not a letter, digit or
+// underscore with an underscore, and prefixes names starting with a digit
+// with an underscore.
+func DefaultNameSanitizer(name string) string {
+	if name == "" {
+		return "_"
+	}
+	s := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+	if unicode.IsDigit([]rune(s)[0]) {
+		s = "_" + s
+	}
+	return s
 }
 
 func (f *fieldPos) mapChildren() {
@@ -172,6 +399,14 @@ func (f *fieldPos) namePath() []string {
 }
 
 // namePath returns the path to the field in json dot notation
+// childPath returns the dotpath of f's child key k.
+func (f *fieldPos) childPath(k string) string {
+	if len(f.path) == 0 {
+		return "$" + k
+	}
+	return f.dotPath() + "." + k
+}
+
 func (f *fieldPos) dotPath() string {
 	var path string = "$"
 	for i, p := range f.path {
@@ -202,23 +437,60 @@ func (f *fieldPos) getValue(m map[string]any) any {
 
 // graft grafts a new field into the schema tree
 func (f *fieldPos) graft(n *fieldPos) {
+	if f == f.root && f.owner.divert(n.name) {
+		return
+	}
 	graft := f.newChild(n.name)
-	graft.arrowType = n.arrowType
-	graft.field = n.field
-	graft.children = append(graft.children, n.children...)
-	graft.mapChildren()
+	graft.copyAttrs(n)
+	// the name given where n was evaluated is kept unless another field holds it
+	if f.nameTaken(n.field.Name, n.name) {
+		name, meta := f.fieldName(n.name)
+		graft.field.Name = name
+		graft.field.Metadata = meta
+	}
+	graft.adopt(n)
 	f.assignChild(graft)
 	f.owner.knownFields.Set(graft.dotPath(), graft)
 	f.owner.untypedFields.Delete(graft.dotPath())
-	f.owner.changes = errors.Join(f.owner.changes, fmt.Errorf("%w %v : %v", ErrFieldAdded, graft.dotPath(), graft.field.Type.String()))
-	if f.field.Type.ID() == arrow.STRUCT {
-		gf := f.field.Type.(*arrow.StructType)
-		var nf []arrow.Field
-		nf = append(nf, gf.Fields()...)
-		nf = append(nf, graft.field)
-		f.field = arrow.Field{Name: f.name, Type: arrow.StructOf(nf...), Nullable: true}
-		if (f.parent != nil) && f.parent.field.Type.ID() == arrow.LIST {
-			f.parent.field = arrow.Field{Name: f.parent.name, Type: arrow.ListOf(f.field.Type.(*arrow.StructType)), Nullable: true}
+	f.owner.addChange(ErrFieldAdded, graft.dotPath(), graft.field.Type, graft.field.Type.String())
+	f.updateTypes()
+}
+
+// copyAttrs copies the evaluated type attributes of n to f.
+func (f *fieldPos) copyAttrs(n *fieldPos) {
+	f.arrowType = n.arrowType
+	f.field = n.field
+	f.isList, f.isStruct, f.isMap = n.isList, n.isStruct, n.isMap
+	f.intSeen, f.intMin, f.intMax = n.intSeen, n.intMin, n.intMax
+}
+
+// adopt adds copies of n's descendants to f, so that f's tree never shares nodes
+// with the tree of an input being merged.
+func (f *fieldPos) adopt(n *fieldPos) {
+	for _, c := range n.children {
+		a := f.newChild(c.name)
+		a.copyAttrs(c)
+		a.adopt(c)
+		f.assignChild(a)
+	}
+}
+
+// updateTypes rebuilds the types of f and its ancestors from their children, so that
+// a change deep in the tree is reflected in the top level fields.
+func (f *fieldPos) updateTypes() {
+	for a := f; a != nil; a = a.parent {
+		if a.field.Type == nil {
+			continue
+		}
+		switch {
+		case a.field.Type.ID() == arrow.STRUCT:
+			var fields []arrow.Field
+			for _, c := range a.children {
+				fields = append(fields, c.field)
+			}
+			a.field = arrow.Field{Name: a.field.Name, Type: arrow.StructOf(fields...), Metadata: a.field.Metadata, Nullable: true}
+		case isListType(a.field.Type.ID()) && len(a.children) > 0:
+			a.field = arrow.Field{Name: a.field.Name, Type: a.owner.listOf(a.children[0].field.Type), Metadata: a.field.Metadata, Nullable: true}
 		}
 	}
 }
@@ -227,6 +499,8 @@ func (f *fieldPos) graft(n *fieldPos) {
 // Supported type upgrades:
 //
 //		arrow.INT8, arrow.INT16, arrow.INT32, arrow.INT64 => arrow.FLOAT64
+//		arrow.INT8, arrow.INT16, arrow.INT32, arrow.INT64 => arrow.DECIMAL128
+//		arrow.DECIMAL128 => arrow.STRING
 //		arrow.FLOAT16 => arrow.FLOAT32
 //		arrow.FLOAT32 => arrow.FLOAT64
 //	 	arrow.FLOAT64 => arrow.STRING
@@ -234,39 +508,165 @@ func (f *fieldPos) graft(n *fieldPos) {
 //		arrow.DATE32 => arrow.TIMESTAMP
 //		arrow.DATE32 => arrow.STRING
 //		arrow.TIME64 => arrow.STRING
+//		arrow.DURATION => arrow.STRING
 func (o *fieldPos) upgradeType(n *fieldPos, t arrow.Type) error {
 	if !slices.Contains(UpgradableTypes, o.field.Type.ID()) {
 		return fmt.Errorf("%s %v %v", n.dotPath(), n.field.Type.Name(), ErrNotAnUpgradableType.Error())
 	}
-	oldType := o.field.Type.String()
-	// changes to field
 	switch t {
 	case arrow.FLOAT32:
-		o.arrowType = arrow.FLOAT32
-		o.field = arrow.Field{Name: o.name, Type: arrow.PrimitiveTypes.Float32, Nullable: true}
+		o.setType(arrow.PrimitiveTypes.Float32)
 	case arrow.FLOAT64:
-		o.arrowType = arrow.FLOAT64
-		o.field = arrow.Field{Name: o.name, Type: arrow.PrimitiveTypes.Float64, Nullable: true}
+		o.setType(arrow.PrimitiveTypes.Float64)
 	case arrow.STRING:
-		o.arrowType = arrow.STRING
-		o.field = arrow.Field{Name: o.name, Type: arrow.BinaryTypes.String, Nullable: true}
+		o.setType(o.owner.stringType())
 	case arrow.TIMESTAMP:
-		o.arrowType = arrow.TIMESTAMP
-		o.field = arrow.Field{Name: o.name, Type: arrow.FixedWidthTypes.Timestamp_ms, Nullable: true}
+		o.setType(arrow.FixedWidthTypes.Timestamp_ms)
 	}
+	return nil
+}
+
+// setType changes the field's type, propagates the change to its parent
+// and records it in the owner's changes.
+func (o *fieldPos) setType(dt arrow.DataType) {
+	oldType := o.field.Type.String()
+	if len(o.history) == 0 {
+		o.history = append(o.history, o.field.Type.ID())
+	}
+	o.history = append(o.history, dt.ID())
+	// changes to field
+	o.arrowType = dt.ID()
+	o.field = arrow.Field{Name: o.field.Name, Type: dt, Metadata: o.field.Metadata, Nullable: true}
 	// changes to parent
-	switch o.parent.field.Type.ID() {
-	case arrow.LIST:
-		o.parent.field = arrow.Field{Name: o.parent.name, Type: arrow.ListOf(n.field.Type), Nullable: true}
-	case arrow.STRUCT:
-		var fields []arrow.Field
-		for _, c := range o.parent.children {
-			fields = append(fields, c.field)
+	o.parent.updateTypes()
+	o.owner.addChange(ErrFieldTypeChanged, o.dotPath(), o.field.Type, fmt.Sprintf("from %v to %v", oldType, o.field.Type.String()))
+}
+
+// resolveNull changes the Null field o to the type of n, the field at the same path in
+// an input holding a non-null value, adopting its descendants.
+func (o *fieldPos) resolveNull(n *fieldPos) {
+	o.isList, o.isStruct, o.isMap = n.isList, n.isStruct, n.isMap
+	o.intSeen, o.intMin, o.intMax = n.intSeen, n.intMin, n.intMax
+	o.adopt(n)
+	o.setType(n.field.Type)
+}
+
+// setJSONBlob changes the field to a string holding the JSON text of its values,
+// removing its descendants from the known fields.
+func (o *fieldPos) setJSONBlob() {
+	var forget func(f *fieldPos)
+	forget = func(f *fieldPos) {
+		for _, c := range f.children {
+			o.owner.knownFields.Delete(c.dotPath())
+			forget(c)
 		}
-		o.parent.field = arrow.Field{Name: o.parent.name, Type: arrow.StructOf(fields...), Nullable: true}
 	}
-	o.owner.changes = errors.Join(o.owner.changes, fmt.Errorf("%w %v : from %v to %v", ErrFieldTypeChanged, o.dotPath(), oldType, o.field.Type.String()))
-	return nil
+	forget(o)
+	o.children, o.childmap = nil, make(map[string]*fieldPos)
+	o.isList, o.isStruct, o.isMap = false, false, false
+	o.jsonBlob = true
+	keys := slices.Concat(o.field.Metadata.Keys(), []string{reader.JSONBlobKey})
+	values := slices.Concat(o.field.Metadata.Values(), []string{"true"})
+	o.field.Metadata = arrow.NewMetadata(keys, values)
+	o.setType(o.owner.stringType())
+}
+
+// valueKind returns the kind of JSON value of type dt: "object", "array" or "scalar".
+func valueKind(dt arrow.DataType) string {
+	switch dt.ID() {
+	case arrow.STRUCT, arrow.MAP:
+		return "object"
+	case arrow.LIST, arrow.LARGE_LIST, arrow.FIXED_SIZE_LIST:
+		return "array"
+	}
+	return "scalar"
+}
+
+// observeInt widens the field's observed integer value range to include i.
+func (f *fieldPos) observeInt(i int64) {
+	if !f.intSeen {
+		f.intSeen, f.intMin, f.intMax = true, i, i
+		return
+	}
+	f.intMin = min(f.intMin, i)
+	f.intMax = max(f.intMax, i)
+}
+
+// mergeIntRange widens the field's observed integer value range to include n's.
+func (f *fieldPos) mergeIntRange(n *fieldPos) {
+	if !n.intSeen {
+		return
+	}
+	f.observeInt(n.intMin)
+	f.observeInt(n.intMax)
+}
+
+// finalField returns field, the Arrow field of f, as output by Schema(): with integer
+// types narrowed to the smallest type fitting the range of values observed at each
+// path if WithExactIntegerWidth is set, string fields tagged with their semantic
+// type if WithSemanticDetection is set, arrays of constant length as fixed size
+// lists if WithInferFixedSizeList is set, GeoJSON geometries tagged with their type if
+// WithGeoJSONDetection is set and columns set with WithRunEndEncoded as run-end encoded.
+func (f *fieldPos) finalField(field arrow.Field) arrow.Field {
+	f.owner.building = f
+	switch ft := field.Type.(type) {
+	case *arrow.StructType:
+		fields := make([]arrow.Field, ft.NumFields())
+		for i, sf := range ft.Fields() {
+			fields[i] = sf
+			for _, c := range f.children {
+				if c.field.Name == sf.Name {
+					fields[i] = c.finalField(sf)
+					break
+				}
+			}
+		}
+		f.owner.building = f
+		if f.owner.sortedFields {
+			sortFields(fields)
+		}
+		field.Type = arrow.StructOf(fields...)
+		if f.owner.geoJSON {
+			field = f.tagGeoJSON(field)
+		}
+	case *arrow.ListType:
+		if len(f.children) > 0 {
+			field.Type = arrow.ListOf(f.children[0].finalField(ft.ElemField()).Type)
+			f.owner.building = f
+		} else {
+			field = f.tagEnum(f.tagSemantic(field))
+		}
+		if fsl := f.fixedSizeList(field.Type.(*arrow.ListType).Elem()); fsl != nil {
+			field.Type = fsl
+		}
+	case *arrow.LargeListType:
+		if len(f.children) > 0 {
+			field.Type = arrow.LargeListOf(f.children[0].finalField(ft.ElemField()).Type)
+			f.owner.building = f
+		} else {
+			field = f.tagEnum(f.tagSemantic(field))
+		}
+		if fsl := f.fixedSizeList(field.Type.(*arrow.LargeListType).Elem()); fsl != nil {
+			field.Type = fsl
+		}
+	default:
+		if f.owner.exactIntegerWidth && f.intSeen && arrow.IsInteger(ft.ID()) {
+			field.Type = narrowestInt(f.intMin, f.intMax)
+		}
+		field = f.tagEnum(f.tagSemantic(field))
+		if f.parent == f.root && f.owner.runEndEncoded[f.dotPath()] {
+			field.Type = arrow.RunEndEncodedOf(arrow.PrimitiveTypes.Int32, field.Type)
+		}
+	}
+	if len(f.owner.fieldMeta) > 0 {
+		field = f.tagMeta(field)
+	}
+	return field
+}
+
+// sortFields sorts fields by name.
+func sortFields(fields []arrow.Field) {
+	slices.SortStableFunc(fields, func(a, b arrow.Field) int { return strings.Compare(a.Name, b.Name) })
 }
 
 func errWrap(f *fieldPos) error {
@@ -285,41 +685,85 @@ func errWrap(f *fieldPos) error {
 // mapToArrow traverses a map[string]any and creates a fieldPos tree from
 // which an Arrow schema can be generated.
 func mapToArrow(f *fieldPos, m map[string]any) {
-	for k, v := range m {
+	f.keepNames(m)
+	defer func() { f.keptNames = nil }()
+	for _, in := range f.orderedKeys(m) {
+		k := in
+		if f == f.root && f.owner.metaKey != "" && k == f.owner.metaKey {
+			if !f.owner.copying {
+				f.owner.observeMeta(m[k])
+			}
+			continue
+		}
+		if f.owner.caseInsensitiveMerge {
+			var ok bool
+			if k, ok = f.canonicalKey(in, m); !ok {
+				continue
+			}
+		}
+		if f == f.root && !f.pooled && f.owner.divert(k) {
+			continue
+		}
+		v := m[in]
+		if s, ok := v.(string); ok && f.owner.blankAsNull && reader.IsBlank(s) {
+			v = nil
+		}
 		child := f.newChild(k)
+		name, meta := f.fieldName(k)
+		if f.owner.collectStats && !f.owner.copying {
+			f.owner.observeStat(child.dotPath(), v)
+		}
+		if f.owner.observingPresence && !f.owner.copying {
+			f.owner.observePresence(child.dotPath(), v == nil)
+		}
+		if isNested(v) && child.tooDeep(child.depth) {
+			child.field = buildArrowField(name, f.owner.stringType(), meta, true)
+			child.arrowType = child.field.Type.ID()
+			f.assignChild(child)
+			continue
+		}
 		switch t := v.(type) {
 		case map[string]any:
+			if f.owner.geoJSON && !f.owner.copying {
+				f.owner.observeGeoJSON(child.dotPath(), t)
+			}
 			mapToArrow(child, t)
 			var fields []arrow.Field
 			for _, c := range child.children {
 				fields = append(fields, c.field)
 			}
 			if len(child.children) != 0 {
-				child.field = buildArrowField(k, arrow.StructOf(fields...), arrow.Metadata{}, true)
+				child.field = buildArrowField(name, arrow.StructOf(fields...), meta, true)
 				f.assignChild(child)
 			} else {
 				child.arrowType = arrow.STRUCT
 				child.isStruct = true
-				f.owner.untypedFields.Set(child.dotPath(), child)
+				child.setUntyped()
 			}
 		case []any:
+			if f.owner.inferFixedSizeList && !f.owner.copying {
+				f.owner.observeListLen(child.dotPath(), len(t))
+			}
 			if len(t) <= 0 {
 				child.arrowType = arrow.LIST
 				child.isList = true
-				f.owner.untypedFields.Set(child.dotPath(), child)
+				child.setUntyped()
 				f.err = errors.Join(f.err, fmt.Errorf("%v : %v", ErrUndefinedArrayElementType, child.namePath()))
 			} else {
 				et := sliceElemType(child, t)
 				child.isList = true
-				child.field = buildArrowField(k, arrow.ListOf(et), arrow.Metadata{}, true)
+				child.field = buildArrowField(name, f.owner.listOf(et), meta, true)
 				f.assignChild(child)
 			}
 		case nil:
 			child.arrowType = arrow.NULL
-			f.owner.untypedFields.Set(child.dotPath(), child)
+			if !f.owner.copying {
+				f.owner.nullCounts[child.dotPath()]++
+			}
+			child.setUntyped()
 			f.err = errors.Join(f.err, fmt.Errorf("%v : %v", ErrUndefinedFieldType, child.namePath()))
 		default:
-			child.field = buildArrowField(k, goType2Arrow(child, v), arrow.Metadata{}, true)
+			child.field = buildArrowField(name, goType2Arrow(child, v), meta, true)
 			f.assignChild(child)
 		}
 	}
@@ -331,9 +775,42 @@ func mapToArrow(f *fieldPos, m map[string]any) {
 	f.field = arrow.Field{Name: f.name, Type: arrow.StructOf(fields...), Nullable: true}
 }
 
+// orderedKeys returns the keys of m in the order in which they should be evaluated.
+// Keys are sorted if WithSortedFields, renames or a name sanitizer are set. Otherwise
+// top-level keys follow the owner's first record key order if one is set, keys not
+// found in it follow in map order.
+func (f *fieldPos) orderedKeys(m map[string]any) []string {
+	keys := slices.Collect(maps.Keys(m))
+	if f.owner.nameSanitizer != nil || len(f.owner.renames) > 0 || f.owner.sortedFields || f.owner.caseInsensitiveMerge {
+		// Sorted keys make collision suffixes and canonical names deterministic.
+		slices.Sort(keys)
+	}
+	if f == f.root && len(f.owner.keyOrder) > 0 && !f.owner.sortedFields {
+		ordered := make([]string, 0, len(keys))
+		for _, k := range f.owner.keyOrder {
+			if _, ok := m[k]; ok {
+				ordered = append(ordered, k)
+			}
+		}
+		for _, k := range keys {
+			if !slices.Contains(ordered, k) {
+				ordered = append(ordered, k)
+			}
+		}
+		keys = ordered
+	}
+	return keys
+}
+
 // sliceElemType evaluates the slice type and returns an Arrow DataType
 // to be used in building an Arrow Field.
 func sliceElemType(f *fieldPos, v []any) arrow.DataType {
+	if isNested(v[0]) && f.tooDeep(f.depth+1) {
+		return f.owner.stringType()
+	}
+	if kinds := elemKinds(v); len(kinds) > 1 {
+		return f.coerceElems(kinds)
+	}
 	switch ft := v[0].(type) {
 	case map[string]any:
 		child := f.newChild(f.name + ".elem")
@@ -352,13 +829,127 @@ func sliceElemType(f *fieldPos, v []any) arrow.DataType {
 		child := f.newChild(f.name + ".elem")
 		et := sliceElemType(child, v[0].([]any))
 		f.assignChild(child)
-		return arrow.ListOf(et)
+		return f.owner.listOf(et)
 	default:
+		return scalarElemType(f, v)
+	}
+}
+
+// elemKinds returns the kinds of the non-null elements of v: "object", "array" or
+// "scalar".
+func elemKinds(v []any) []string {
+	var kinds []string
+	for _, e := range v {
+		var kind string
+		switch e.(type) {
+		case nil:
+			continue
+		case map[string]any:
+			kind = "object"
+		case []any:
+			kind = "array"
+		default:
+			kind = "scalar"
+		}
+		if !slices.Contains(kinds, kind) {
+			kinds = append(kinds, kind)
+		}
+	}
+	return kinds
+}
+
+// scalarElemType returns the common type of the scalar elements of v: their type if they
+// all have the same, float64 for a mix of integers and floats, a string otherwise.
+func scalarElemType(f *fieldPos, v []any) arrow.DataType {
+	var dt arrow.DataType
+	var kinds []string
+	evaluated := make(map[any]bool)
+	for _, e := range v {
+		if e == nil {
+			continue
+		}
+		key, ok := elemTypeKey(e)
+		if ok {
+			if evaluated[key] {
+				continue
+			}
+			evaluated[key] = true
+		}
+		et := goType2Arrow(f, e)
+		if !slices.Contains(kinds, et.String()) {
+			kinds = append(kinds, et.String())
+		}
+		switch {
+		case dt == nil || arrow.TypeEqual(dt, et):
+			dt = et
+		case isIntOrFloat64(dt) && isIntOrFloat64(et):
+			dt = arrow.PrimitiveTypes.Float64
+		default:
+			return f.coerceElems(kinds)
+		}
+	}
+	if dt == nil {
 		return goType2Arrow(f, v)
 	}
-	return nil
+	f.arrowType = dt.ID()
+	return dt
+}
+
+func isIntOrFloat64(dt arrow.DataType) bool {
+	return dt.ID() == arrow.INT64 || dt.ID() == arrow.FLOAT64
+}
+
+// coerceElems returns the string type for the elements of a list whose elements are of
+// different kinds, noting the coercion in the owner's changes the first time it is seen.
+// Nested elements are loaded as JSON strings.
+func (f *fieldPos) coerceElems(kinds []string) arrow.DataType {
+	dt := f.owner.stringType()
+	f.arrowType = dt.ID()
+	if _, ok := f.owner.knownFields.Get(f.dotPath()); !ok {
+		f.owner.addChange(ErrFieldCoerced, f.dotPath(), dt, fmt.Sprintf("mixed array elements %v, kept as strings", kinds))
+	}
+	return dt
+}
+
+// isNested reports whether v is a JSON object or array.
+func isNested(v any) bool {
+	switch v.(type) {
+	case map[string]any, []any:
+		return true
+	}
+	return false
 }
 
+// tooDeep reports whether nested content of f at depth lies beyond the maximum depth set
+// with WithMaxDepth, noting the field in the owner's changes the first time it is seen.
+func (f *fieldPos) tooDeep(depth int32) bool {
+	if f.owner.maxDepth <= 0 || int(depth) < f.owner.maxDepth {
+		return false
+	}
+	if _, ok := f.owner.knownFields.Get(f.dotPath()); !ok {
+		f.owner.addChange(ErrFieldMaxDepth, f.dotPath(), f.owner.stringType(), fmt.Sprintf("depth %d, kept as JSON string", depth))
+	}
+	return true
+}
+
+// listOf returns a list type of dt elements, a large list if WithLargeTypes is set.
+func (u *Bodkin) listOf(dt arrow.DataType) arrow.DataType {
+	if u.largeTypes {
+		return arrow.LargeListOf(dt)
+	}
+	return arrow.ListOf(dt)
+}
+
+// stringType returns the string type, a large string if WithLargeTypes is set.
+func (u *Bodkin) stringType() arrow.DataType {
+	if u.largeTypes {
+		return arrow.BinaryTypes.LargeString
+	}
+	return arrow.BinaryTypes.String
+}
+
+func isListType(t arrow.Type) bool { return t == arrow.LIST || t == arrow.LARGE_LIST }
+
 func buildArrowField(n string, t arrow.DataType, m arrow.Metadata, nullable bool) arrow.Field {
 	return arrow.Field{
 		Name:     n,
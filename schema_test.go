@@ -0,0 +1,282 @@
+package bodkin
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/loicalleyne/bodkin/reader"
+)
+
+func TestTreatBlankAsNull(t *testing.T) {
+	for _, blank := range []string{"", "  ", "\t\n", "\u00a0"} {
+		t.Run(strconv.Quote(blank), func(t *testing.T) {
+			input := `{"n":` + strconv.Quote(blank) + `,"s":` + strconv.Quote(blank) + `}`
+			u := NewBodkin(WithTreatBlankAsNull())
+			for _, in := range []string{input, `{"n":5,"s":"x"}`} {
+				if err := u.Unify(in); err != nil {
+					t.Fatal(err)
+				}
+			}
+			sc, err := u.Schema()
+			if err != nil {
+				t.Fatal(err)
+			}
+			// blank values are not evaluated, n takes the type of its first non-blank value
+			if f, ok := sc.FieldsByName("n"); !ok || f[0].Type.ID() != arrow.INT64 {
+				t.Fatalf("n inferred as %v, want int64", f)
+			}
+
+			r, err := u.NewReader()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer r.Release()
+			rec, err := r.ReadToRecord([]byte(input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer rec.Release()
+			for _, name := range []string{"n", "s"} {
+				if col := rec.Column(sc.FieldIndices(name)[0]); !col.IsNull(0) {
+					t.Errorf("%s loaded as %v, want null", name, col)
+				}
+			}
+		})
+	}
+}
+
+func TestNullColumnsResolvedLater(t *testing.T) {
+	u := NewBodkin(WithNullColumnsAsNullType())
+	for _, in := range []string{`{"a":1,"b":null,"c":null}`, `{"a":2,"b":null,"c":null}`} {
+		if err := u.Unify(in); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sc, err := u.Schema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"b", "c"} {
+		if f, ok := sc.FieldsByName(name); !ok || f[0].Type.ID() != arrow.NULL {
+			t.Fatalf("%s = %v, want null", name, f)
+		}
+	}
+
+	// later non-null values replace the Null type of the columns resolved by Schema()
+	if err := u.Unify(`{"a":3,"b":"x","c":{"d":1.5}}`); err != nil {
+		t.Fatal(err)
+	}
+	sc, err = u.Schema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]arrow.DataType{
+		"b": arrow.BinaryTypes.String,
+		"c": arrow.StructOf(arrow.Field{Name: "d", Type: arrow.PrimitiveTypes.Float64, Nullable: true}),
+	}
+	for name, dt := range want {
+		if f, ok := sc.FieldsByName(name); !ok || !arrow.TypeEqual(f[0].Type, dt) {
+			t.Errorf("%s = %v, want %v", name, f, dt)
+		}
+	}
+	r, err := u.NewReader()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Release()
+	rec, err := r.ReadToRecord([]byte(`{"a":3,"b":"x","c":{"d":1.5}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec.Release()
+}
+
+func TestScalarArrayElemType(t *testing.T) {
+	tests := []struct {
+		in   string
+		want arrow.DataType
+	}{
+		{`["a","b","a"]`, arrow.BinaryTypes.String},
+		{`["2024-01-01","2024-01-02","2024-01-01"]`, arrow.FixedWidthTypes.Date32},
+		{`["2024-01-01","2024-01-01","abc"]`, arrow.BinaryTypes.String},
+		{`[1,1,2.5]`, arrow.PrimitiveTypes.Float64},
+		{`[1.5,2.5,true]`, arrow.BinaryTypes.String},
+		{`[1,null,1]`, arrow.PrimitiveTypes.Int64},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			u := NewBodkin(WithInferTimeUnits())
+			if err := u.Unify(`{"l":` + tt.in + `}`); err != nil {
+				t.Fatal(err)
+			}
+			sc, err := u.Schema()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := sc.Field(0).Type.(*arrow.ListType).Elem(); !arrow.TypeEqual(got, tt.want) {
+				t.Errorf("elements inferred as %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// BenchmarkUnifyScalarArrays unifies long arrays of repeated strings and of floats.
+func BenchmarkUnifyScalarArrays(b *testing.B) {
+	var sb strings.Builder
+	sb.WriteString(`{"tags":[`)
+	for i := range 1000 {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		fmt.Fprintf(&sb, `"tag%d"`, i%20)
+	}
+	sb.WriteString(`],"n":[`)
+	for i := range 1000 {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		fmt.Fprintf(&sb, `%d.5`, i)
+	}
+	sb.WriteString(`]}`)
+	in := sb.String()
+	u := NewBodkin(WithInferTimeUnits())
+	b.ReportAllocs()
+	for range b.N {
+		u.Unify(in)
+	}
+}
+
+func TestSanitizedNameCollision(t *testing.T) {
+	tests := []struct {
+		inputs []string
+		want   map[string]string
+	}{
+		{[]string{`{"a b":2,"a_b":3}`}, map[string]string{"a_b": "3", "a_b_2": "2"}},
+		// sanitized keys are suffixed in sorted order
+		{[]string{`{"a_b":3,"a-b":1,"a b":2}`}, map[string]string{"a_b": "3", "a_b_2": "2", "a_b_3": "1"}},
+		// a name taken by an earlier input is kept by it
+		{[]string{`{"a b":2}`, `{"a b":2,"a_b":3}`}, map[string]string{"a_b": "2", "a_b_2": "3"}},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.inputs, " "), func(t *testing.T) {
+			u := NewBodkin(WithNameSanitizer(nil))
+			for _, in := range tt.inputs {
+				if err := u.Unify(in); err != nil {
+					t.Fatal(err)
+				}
+			}
+			r, err := u.NewReader()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer r.Release()
+			rec, err := r.ReadToRecord([]byte(tt.inputs[len(tt.inputs)-1]))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer rec.Release()
+			if int(rec.NumCols()) != len(tt.want) {
+				t.Fatalf("schema = %v, want fields %v", rec.Schema(), tt.want)
+			}
+			for name, want := range tt.want {
+				idx := rec.Schema().FieldIndices(name)
+				if len(idx) != 1 || rec.Column(idx[0]).ValueStr(0) != want {
+					t.Errorf("%s = %v, want %s", name, idx, want)
+				}
+			}
+		})
+	}
+}
+
+func TestResolveEmptyAs(t *testing.T) {
+	const input = `{"a":1,"n":null,"o":{},"p":{"q":[]},"l":[]}`
+	tests := []struct {
+		as   arrow.Type
+		want map[string]string
+	}{
+		{arrow.INT64, map[string]string{"n": "int64", "o": "utf8", "p": "utf8", "l": "list<item: int64, nullable>"}},
+		{arrow.STRING, map[string]string{"n": "utf8", "o": "utf8", "p": "utf8", "l": "list<item: utf8, nullable>"}},
+		{arrow.NULL, map[string]string{"n": "null", "o": "null", "p": "null", "l": "list<item: null, nullable>"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.as.String(), func(t *testing.T) {
+			u := NewBodkin(WithResolveEmptyAs(tt.as))
+			if err := u.Unify(input); err != nil {
+				t.Fatal(err)
+			}
+			sc, err := u.Schema()
+			if err != nil {
+				t.Fatal(err)
+			}
+			for name, want := range tt.want {
+				if f, ok := sc.FieldsByName(name); !ok || f[0].Type.String() != want {
+					t.Errorf("%s resolved as %v, want %s", name, f, want)
+				}
+			}
+			if errs := u.Err(); len(errs) != 0 {
+				t.Errorf("Err() = %v, want none", errs)
+			}
+			r, err := u.NewReader()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer r.Release()
+			rec, err := r.ReadToRecord([]byte(input))
+			if err != nil || rec == nil {
+				t.Fatalf("ReadToRecord() = %v, %v", rec, err)
+			}
+			defer rec.Release()
+			if tt.as != arrow.NULL {
+				if got := rec.Column(sc.FieldIndices("o")[0]).ValueStr(0); got != "{}" {
+					t.Errorf("o loaded as %s, want {}", got)
+				}
+			}
+		})
+	}
+}
+
+func TestLargeTypes(t *testing.T) {
+	u := NewBodkin(WithLargeTypes())
+	for _, in := range []string{
+		`{"tags":["a","b"],"items":[{"n":1}],"s":"x"}`,
+		`{"tags":[],"items":[{"n":2},{"n":3}],"s":"y"}`,
+	} {
+		if err := u.Unify(in); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sc, err := u.Schema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := reader.NewReader(sc, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Release()
+	rec, err := r.ReadToRecord([]byte(`{"tags":["c","d","e"],"items":[{"n":4}],"s":"z"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rec.Release()
+	for _, name := range []string{"tags", "items"} {
+		col := rec.Column(sc.FieldIndices(name)[0])
+		l, ok := col.(*array.LargeList)
+		if !ok {
+			t.Fatalf("%s column = %T, want *array.LargeList", name, col)
+		}
+		if start, end := l.ValueOffsets(0); end-start == 0 {
+			t.Errorf("%s = %v, want the datum's elements", name, l)
+		}
+	}
+	if got := sc.Field(sc.FieldIndices("tags")[0]).Type; !arrow.TypeEqual(got, arrow.LargeListOf(arrow.BinaryTypes.LargeString)) {
+		t.Errorf("tags type = %v, want large_list<large_utf8>", got)
+	}
+	if s, ok := rec.Column(sc.FieldIndices("s")[0]).(*array.LargeString); !ok || s.Value(0) != "z" {
+		t.Errorf("s column = %v, want large_utf8 z", rec.Column(sc.FieldIndices("s")[0]))
+	}
+}
@@ -0,0 +1,72 @@
+package bodkin
+
+import (
+	"net/netip"
+	"regexp"
+	"slices"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+// SemanticKey is the Arrow field metadata key of semantic types set with WithSemanticDetection.
+const SemanticKey = "semantic"
+
+var emailMatcher = regexp.MustCompile(`^[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}$`)
+
+// semanticCounts counts the string values seen at a path matching each semantic type.
+type semanticCounts struct {
+	values, ipv4, ipv6, email int
+}
+
+// observeSemantic counts the semantic type of string value s at dotpath.
+func (u *Bodkin) observeSemantic(dotpath, s string) {
+	c, ok := u.semantics[dotpath]
+	if !ok {
+		c = new(semanticCounts)
+		u.semantics[dotpath] = c
+	}
+	c.values++
+	if addr, err := netip.ParseAddr(s); err == nil {
+		if addr.Is4() {
+			c.ipv4++
+		} else {
+			c.ipv6++
+		}
+		return
+	}
+	if emailMatcher.MatchString(s) {
+		c.email++
+	}
+}
+
+// semantic returns the semantic type of the values seen at dotpath, or "".
+func (u *Bodkin) semantic(dotpath string) string {
+	c, ok := u.semantics[dotpath]
+	if !ok || c.values == 0 {
+		return ""
+	}
+	for _, t := range []struct {
+		name    string
+		matched int
+	}{{"ipv4", c.ipv4}, {"ipv6", c.ipv6}, {"email", c.email}} {
+		if float64(t.matched)/float64(c.values) >= u.semanticFraction {
+			return t.name
+		}
+	}
+	return ""
+}
+
+// tagSemantic returns field with the semantic type of f's values added to its metadata.
+func (f *fieldPos) tagSemantic(field arrow.Field) arrow.Field {
+	if f.owner.semanticFraction <= 0 {
+		return field
+	}
+	st := f.owner.semantic(f.dotPath())
+	if st == "" {
+		return field
+	}
+	keys := slices.Concat(field.Metadata.Keys(), []string{SemanticKey})
+	values := slices.Concat(field.Metadata.Values(), []string{st})
+	field.Metadata = arrow.NewMetadata(keys, values)
+	return field
+}
@@ -0,0 +1,96 @@
+package bodkin
+
+import (
+	"fmt"
+	"hash/maphash"
+	"math"
+	"math/bits"
+)
+
+// FieldStat holds the statistics of a field's values collected with WithCollectStats.
+type FieldStat struct {
+	// Number of inputs in which the field was present with a null value.
+	Nulls int
+	// Number of inputs in which the field was present with a non-null value.
+	NonNulls int
+	// Approximate number of distinct scalar values, estimated with HyperLogLog
+	// with a standard error of about 1.6%. Zero for objects and arrays.
+	Distinct uint64
+}
+
+// fieldStats accumulates the statistics of a field.
+type fieldStats struct {
+	nulls, nonNulls int
+	distinct        hyperLogLog
+}
+
+// FieldStats returns the statistics of the values of each field seen to date, keyed by
+// dotpath, if WithCollectStats is set. Fields absent from an input are not counted.
+func (u *Bodkin) FieldStats() map[string]FieldStat {
+	stats := make(map[string]FieldStat, len(u.stats))
+	for p, s := range u.stats {
+		stats[p] = FieldStat{Nulls: s.nulls, NonNulls: s.nonNulls, Distinct: s.distinct.count()}
+	}
+	return stats
+}
+
+// observeStat adds value v of the field at dotpath to the field's statistics.
+func (u *Bodkin) observeStat(dotpath string, v any) {
+	s, ok := u.stats[dotpath]
+	if !ok {
+		s = new(fieldStats)
+		u.stats[dotpath] = s
+	}
+	switch t := v.(type) {
+	case nil:
+		s.nulls++
+	case map[string]any, []any:
+		s.nonNulls++
+	case string:
+		s.nonNulls++
+		s.distinct.add(maphash.String(u.statSeed, t))
+	default:
+		s.nonNulls++
+		s.distinct.add(maphash.String(u.statSeed, fmt.Sprint(t)))
+	}
+}
+
+// hllPrecision is the number of hash bits used to select a HyperLogLog register.
+const hllPrecision = 12
+
+// hyperLogLog estimates the number of distinct hashed values added to it.
+type hyperLogLog struct {
+	registers []uint8
+}
+
+func (h *hyperLogLog) add(x uint64) {
+	if h.registers == nil {
+		h.registers = make([]uint8, 1<<hllPrecision)
+	}
+	idx := x >> (64 - hllPrecision)
+	// the guard bit bounds the rank if the remaining bits are all zero
+	rank := uint8(bits.LeadingZeros64(x<<hllPrecision|1<<(hllPrecision-1))) + 1
+	if rank > h.registers[idx] {
+		h.registers[idx] = rank
+	}
+}
+
+func (h *hyperLogLog) count() uint64 {
+	if h.registers == nil {
+		return 0
+	}
+	m := float64(len(h.registers))
+	sum, zeros := 0.0, 0
+	for _, r := range h.registers {
+		sum += math.Ldexp(1, -int(r))
+		if r == 0 {
+			zeros++
+		}
+	}
+	est := 0.7213 / (1 + 1.079/m) * m * m / sum
+	if est <= 2.5*m && zeros > 0 {
+		// linear counting is more accurate for small cardinalities
+		est = m * math.Log(m/float64(zeros))
+	}
+	return uint64(est + 0.5)
+}
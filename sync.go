@@ -0,0 +1,92 @@
+package bodkin
+
+import (
+	"sync"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/loicalleyne/bodkin/reader"
+)
+
+// SyncBodkin wraps a Bodkin with a mutex so it can be used from multiple goroutines.
+// Calls are serialized: inputs unified concurrently are merged one at a time, in
+// no particular order.
+type SyncBodkin struct {
+	mu sync.Mutex
+	u  *Bodkin
+}
+
+// NewSyncBodkin returns a new SyncBodkin wrapping a Bodkin created with opts.
+func NewSyncBodkin(opts ...Option) *SyncBodkin {
+	return &SyncBodkin{u: NewBodkin(opts...)}
+}
+
+// Do calls fn with the wrapped Bodkin while holding the lock, for methods without
+// a SyncBodkin equivalent. The Bodkin must not be retained after fn returns.
+func (s *SyncBodkin) Do(fn func(u *Bodkin)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(s.u)
+}
+
+// Unify calls Bodkin.Unify.
+func (s *SyncBodkin) Unify(a any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.u.Unify(a)
+}
+
+// UnifyAtPath calls Bodkin.UnifyAtPath.
+func (s *SyncBodkin) UnifyAtPath(a any, mergeAt string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.u.UnifyAtPath(a, mergeAt)
+}
+
+// Schema calls Bodkin.Schema.
+func (s *SyncBodkin) Schema() (*arrow.Schema, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.u.Schema()
+}
+
+// LastSchema calls Bodkin.LastSchema.
+func (s *SyncBodkin) LastSchema() (*arrow.Schema, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.u.LastSchema()
+}
+
+// NewReader calls Bodkin.NewReader. The returned Reader is not protected by the lock.
+func (s *SyncBodkin) NewReader(opts ...reader.Option) (*reader.DataReader, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.u.NewReader(opts...)
+}
+
+// Changes calls Bodkin.Changes.
+func (s *SyncBodkin) Changes() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.u.Changes()
+}
+
+// Err calls Bodkin.Err.
+func (s *SyncBodkin) Err() []Field {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.u.Err()
+}
+
+// Paths calls Bodkin.Paths.
+func (s *SyncBodkin) Paths() []Field {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.u.Paths()
+}
+
+// Count calls Bodkin.Count.
+func (s *SyncBodkin) Count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.u.Count()
+}
@@ -0,0 +1,116 @@
+package bodkin
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+// syncTestInputs are inputs adding fields and changing types as they are unified.
+var syncTestInputs = []string{
+	`{"id":1,"name":"a","tags":["x"]}`,
+	`{"id":2,"score":1.5,"nested":{"ok":true}}`,
+	`{"id":3.5,"name":null,"nested":{"ok":false,"n":1}}`,
+	`{"id":4,"tags":["y","z"],"extra":{"k":"v"}}`,
+}
+
+// Run with -race.
+func TestSyncBodkinConcurrentUnify(t *testing.T) {
+	// with type conversion the merged types don't depend on the order goroutines
+	// unify in, ie. id is float64 whether 3.5 is seen before or after an integer
+	s := NewSyncBodkin(WithTypeConversion())
+	var wg sync.WaitGroup
+	for g := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 50 {
+				if err := s.Unify(syncTestInputs[(g+i)%len(syncTestInputs)]); err != nil {
+					t.Error(err)
+					return
+				}
+				if i%10 == 0 {
+					if _, err := s.Schema(); err != nil {
+						t.Error(err)
+						return
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	// the first input is not counted, it is the initial evaluation
+	if got := s.Count(); got != 8*50-1 {
+		t.Errorf("Count() = %d, want %d", got, 8*50-1)
+	}
+
+	u := NewBodkin(WithTypeConversion())
+	for _, in := range syncTestInputs {
+		if err := u.Unify(in); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := u.Schema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.Schema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Fields()) != len(want.Fields()) {
+		t.Fatalf("Schema() = %v, want %v", got, want)
+	}
+	for _, f := range want.Fields() {
+		g, ok := got.FieldsByName(f.Name)
+		if !ok || !sameField(g[0], f) {
+			t.Errorf("field %s = %v, want %v", f.Name, g, f)
+		}
+	}
+}
+
+// sameField reports whether a and b are equal, ignoring the order of struct fields,
+// which follows the key order of whichever input first added the struct.
+func sameField(a, b arrow.Field) bool {
+	as, aok := a.Type.(*arrow.StructType)
+	bs, bok := b.Type.(*arrow.StructType)
+	if !aok || !bok {
+		return a.Equal(b)
+	}
+	if a.Name != b.Name || a.Nullable != b.Nullable || as.NumFields() != bs.NumFields() {
+		return false
+	}
+	for _, f := range bs.Fields() {
+		i, ok := as.FieldIdx(f.Name)
+		if !ok || !sameField(as.Field(i), f) {
+			return false
+		}
+	}
+	return true
+}
+
+func BenchmarkSyncBodkinUnify(b *testing.B) {
+	b.Run("Bodkin", func(b *testing.B) {
+		u := NewBodkin()
+		b.ReportAllocs()
+		for i := range b.N {
+			u.Unify(syncTestInputs[i%len(syncTestInputs)])
+		}
+	})
+	for _, procs := range []int{1, 4} {
+		b.Run(fmt.Sprintf("SyncBodkin/parallel-%d", procs), func(b *testing.B) {
+			s := NewSyncBodkin()
+			b.ReportAllocs()
+			b.SetParallelism(procs)
+			b.RunParallel(func(pb *testing.PB) {
+				i := 0
+				for pb.Next() {
+					s.Unify(syncTestInputs[i%len(syncTestInputs)])
+					i++
+				}
+			})
+		})
+	}
+}
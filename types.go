@@ -3,33 +3,69 @@ package bodkin
 import (
 	"encoding/json"
 	"fmt"
+	"math"
+	"reflect"
 	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/loicalleyne/bodkin/reader"
 )
 
+// isBoolToken reports whether s is one of the tokens set with WithBoolTokens.
+func (u *Bodkin) isBoolToken(s string) bool {
+	return slices.Contains(u.boolTrue, s) || slices.Contains(u.boolFalse, s)
+}
+
 // goType2Arrow maps a Go type to an Arrow DataType.
 func goType2Arrow(f *fieldPos, gt any) arrow.DataType {
 	var dt arrow.DataType
+	if len(f.owner.date64) > 0 && f.owner.date64[f.dotPath()] && isDate64Value(gt) {
+		f.arrowType = arrow.DATE64
+		return arrow.FixedWidthTypes.Date64
+	}
 	switch t := gt.(type) {
 	case []any:
 		return goType2Arrow(f, t[0])
 	case json.Number:
-		if _, err := t.Int64(); err == nil {
+		if f.owner.rawNumbers[f.dotPath()] {
+			dt = f.owner.stringType()
+			f.arrowType = dt.ID()
+			return dt
+		}
+		if f.owner.isBoolToken(t.String()) {
+			f.arrowType = arrow.BOOL
+			return arrow.FixedWidthTypes.Boolean
+		}
+		if i, err := t.Int64(); err == nil && !f.owner.numericAsFloat64 {
 			f.arrowType = arrow.INT64
 			dt = arrow.PrimitiveTypes.Int64
+			f.observeInt(i)
+		} else if !f.owner.numericAsFloat64 && integerMatcher.MatchString(t.String()) {
+			// beyond the range of int64
+			dt = f.owner.bigIntegerType(t.String())
+			f.arrowType = dt.ID()
 		} else {
 			f.arrowType = arrow.FLOAT64
 			dt = arrow.PrimitiveTypes.Float64
 		}
+	case map[any]any:
+		f.arrowType = arrow.MAP
+		f.isMap = true
+		dt = mapType(f, t)
 	case time.Time:
 		f.arrowType = arrow.TIMESTAMP
 		dt = arrow.FixedWidthTypes.Timestamp_us
+	case time.Duration:
+		f.arrowType = arrow.DURATION
+		dt = arrow.FixedWidthTypes.Duration_ns
 		// either 32 or 64 bits
 	case int:
 		f.arrowType = arrow.INT64
 		dt = arrow.PrimitiveTypes.Int64
+		f.observeInt(int64(t))
 	// the set of all signed  8-bit integers (-128 to 127)
 	case int8:
 		f.arrowType = arrow.INT8
@@ -46,6 +82,7 @@ func goType2Arrow(f *fieldPos, gt any) arrow.DataType {
 	case int64:
 		f.arrowType = arrow.INT64
 		dt = arrow.PrimitiveTypes.Int64
+		f.observeInt(t)
 	// either 32 or 64 bits
 	case uint:
 		f.arrowType = arrow.UINT64
@@ -78,6 +115,14 @@ func goType2Arrow(f *fieldPos, gt any) arrow.DataType {
 		f.arrowType = arrow.BOOL
 		dt = arrow.FixedWidthTypes.Boolean
 	case string:
+		if f.owner.allowNonFinite && reader.IsNonFiniteFloat(t) {
+			f.arrowType = arrow.FLOAT64
+			return arrow.PrimitiveTypes.Float64
+		}
+		if f.owner.isBoolToken(t) {
+			f.arrowType = arrow.BOOL
+			return arrow.FixedWidthTypes.Boolean
+		}
 		if f.owner.inferTimeUnits {
 			for _, r := range timestampMatchers {
 				if r.MatchString(t) {
@@ -94,13 +139,28 @@ func goType2Arrow(f *fieldPos, gt any) arrow.DataType {
 				return arrow.FixedWidthTypes.Time64ns
 			}
 		}
+		if f.owner.isoDurations && reader.IsISODuration(t) {
+			f.arrowType = arrow.DURATION
+			return &arrow.DurationType{Unit: f.owner.durationUnit}
+		}
+		if dt := layoutTimeType(f.owner.timeLayouts, t); dt != nil {
+			f.arrowType = dt.ID()
+			return dt
+		}
 		if !f.owner.quotedValuesAreStrings {
 			if slices.Contains(boolMatcher, t) {
 				f.arrowType = arrow.BOOL
 				return arrow.FixedWidthTypes.Boolean
 			}
 			if integerMatcher.MatchString(t) {
+				i, err := strconv.ParseInt(t, 10, 64)
+				if err != nil {
+					dt = f.owner.bigIntegerType(t)
+					f.arrowType = dt.ID()
+					return dt
+				}
 				f.arrowType = arrow.INT64
+				f.observeInt(i)
 				return arrow.PrimitiveTypes.Int64
 			}
 			if floatMatcher.MatchString(t) {
@@ -108,8 +168,14 @@ func goType2Arrow(f *fieldPos, gt any) arrow.DataType {
 				return arrow.PrimitiveTypes.Float64
 			}
 		}
-		f.arrowType = arrow.STRING
-		dt = arrow.BinaryTypes.String
+		dt = f.owner.stringType()
+		f.arrowType = dt.ID()
+		if f.owner.semanticFraction > 0 && !f.owner.copying {
+			f.owner.observeSemantic(f.dotPath(), t)
+		}
+		if f.owner.maxEnumValues > 0 && !f.owner.copying {
+			f.owner.observeEnum(f.dotPath(), t)
+		}
 	case []byte:
 		f.arrowType = arrow.BINARY
 		dt = arrow.BinaryTypes.Binary
@@ -138,6 +204,95 @@ func goType2Arrow(f *fieldPos, gt any) arrow.DataType {
 	return dt
 }
 
+// bigIntegerType returns the type of an integer beyond the range of int64: a decimal
+// of precision 38 if it fits, a string otherwise or if WithBigIntegersAsString is set.
+func (u *Bodkin) bigIntegerType(s string) arrow.DataType {
+	digits := len(strings.TrimLeft(s, "+-"))
+	if u.bigIntegersAsString || digits > 38 {
+		return u.stringType()
+	}
+	return &arrow.Decimal128Type{Precision: 38, Scale: 0}
+}
+
+// floatNumberKey is the elemTypeKey of JSON numbers with a fraction or an exponent.
+type floatNumberKey struct{}
+
+// elemTypeKey returns the key by which scalarElemType skips list elements whose type
+// was already evaluated: the Go type of e if its Arrow type only depends on it, the
+// value of e if it depends on the value, ie. strings matched against layouts or
+// integers whose range is observed. Maps are always evaluated.
+func elemTypeKey(e any) (any, bool) {
+	switch t := e.(type) {
+	case json.Number:
+		if strings.ContainsAny(t.String(), ".eE") {
+			return floatNumberKey{}, true
+		}
+		return e, true
+	case string, int, int64:
+		return e, true
+	case map[any]any:
+		return nil, false
+	}
+	return reflect.TypeOf(e), true
+}
+
+// mapType returns the Arrow map type of a Go map with non-string keys. The key type is
+// that of the keys if they all have the same Go type, strings otherwise. The item type
+// is the common type of all the values, see commonMapType.
+func mapType(f *fieldPos, m map[any]any) arrow.DataType {
+	var keyType reflect.Type
+	keys := make([]any, 0, len(m))
+	items := make([]any, 0, len(m))
+	mixed := false
+	for k, v := range m {
+		switch {
+		case keyType == nil:
+			keyType = reflect.TypeOf(k)
+		case reflect.TypeOf(k) != keyType:
+			mixed = true
+		}
+		keys = append(keys, k)
+		items = append(items, v)
+	}
+	kt := f.owner.stringType()
+	if !mixed {
+		if dt := commonMapType(f, "key", keys); !arrow.IsNested(dt.ID()) && dt.ID() != arrow.BINARY {
+			kt = dt
+		}
+	}
+	return arrow.MapOf(kt, commonMapType(f, "value", items))
+}
+
+// commonMapType returns the common type of the map keys or values vs, whatever the order
+// of map iteration: their type if they all have the same, float64 for a mix of integers
+// and floats, a string otherwise or if any of them is nested.
+func commonMapType(f *fieldPos, name string, vs []any) arrow.DataType {
+	// detached node, so that evaluating keys and values doesn't affect the map field
+	ef := &fieldPos{owner: f.owner, name: name, path: append(slices.Clone(f.path), name)}
+	var dt arrow.DataType
+	for _, v := range vs {
+		if v == nil {
+			continue
+		}
+		if _, ok := v.(map[any]any); ok || isNested(v) {
+			return f.owner.stringType()
+		}
+		et := goType2Arrow(ef, v)
+		switch {
+		case dt == nil || arrow.TypeEqual(dt, et):
+			dt = et
+		case isIntOrFloat64(dt) && isIntOrFloat64(et):
+			dt = arrow.PrimitiveTypes.Float64
+		default:
+			return f.owner.stringType()
+		}
+	}
+	if dt == nil {
+		return f.owner.stringType()
+	}
+	return dt
+}
+
 func arrowTypeID2Type(f *fieldPos, t arrow.Type) arrow.DataType {
 	var dt arrow.DataType
 	switch t {
@@ -187,6 +342,9 @@ func arrowTypeID2Type(f *fieldPos, t arrow.Type) arrow.DataType {
 	// STRING is a UTF8 variable-length string
 	case arrow.STRING:
 		dt = arrow.BinaryTypes.String
+	// LARGE_STRING is a UTF8 variable-length string with 64-bit offsets
+	case arrow.LARGE_STRING:
+		dt = arrow.BinaryTypes.LargeString
 	// BINARY is a Variable-length byte type (no guarantee of UTF8-ness)
 	case arrow.BINARY:
 		dt = arrow.BinaryTypes.Binary
@@ -208,3 +366,59 @@ func arrowTypeID2Type(f *fieldPos, t arrow.Type) arrow.DataType {
 	}
 	return dt
 }
+
+// layoutTimeType returns the date or timestamp type of s if it is parsed by one of
+// layouts, tried in order, or nil. Layouts without a time of day give arrow.DATE32.
+func layoutTimeType(layouts []string, s string) arrow.DataType {
+	for _, layout := range layouts {
+		if _, err := time.Parse(layout, s); err != nil {
+			continue
+		}
+		midnight := time.Date(2006, 1, 2, 0, 0, 0, 0, time.UTC)
+		if midnight.Format(layout) == midnight.Add(15*time.Hour+4*time.Minute+5*time.Second+time.Millisecond).Format(layout) {
+			return arrow.FixedWidthTypes.Date32
+		}
+		return arrow.FixedWidthTypes.Timestamp_us
+	}
+	return nil
+}
+
+// narrowestInt returns the narrowest Arrow integer type that can hold all values
+// between min and max. Unsigned types are used for non-negative ranges.
+func narrowestInt(min, max int64) arrow.DataType {
+	if min >= 0 {
+		switch {
+		case max <= math.MaxUint8:
+			return arrow.PrimitiveTypes.Uint8
+		case max <= math.MaxUint16:
+			return arrow.PrimitiveTypes.Uint16
+		case max <= math.MaxUint32:
+			return arrow.PrimitiveTypes.Uint32
+		}
+		return arrow.PrimitiveTypes.Int64
+	}
+	switch {
+	case min >= math.MinInt8 && max <= math.MaxInt8:
+		return arrow.PrimitiveTypes.Int8
+	case min >= math.MinInt16 && max <= math.MaxInt16:
+		return arrow.PrimitiveTypes.Int16
+	case min >= math.MinInt32 && max <= math.MaxInt32:
+		return arrow.PrimitiveTypes.Int32
+	}
+	return arrow.PrimitiveTypes.Int64
+}
+
+// isDate64Value reports whether v can be loaded as a date64: an integer number of epoch
+// milliseconds, a YYYY-MM-DD date or a time.
+func isDate64Value(v any) bool {
+	switch t := v.(type) {
+	case json.Number:
+		_, err := t.Int64()
+		return err == nil
+	case string:
+		return integerMatcher.MatchString(t) || dateMatcher.MatchString(t)
+	case time.Time, int, int64:
+		return true
+	}
+	return false
+}
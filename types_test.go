@@ -0,0 +1,115 @@
+package bodkin
+
+import (
+	"math"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+)
+
+func TestMapTypeWidensValues(t *testing.T) {
+	u := NewBodkin()
+	f := &fieldPos{owner: u, name: "m", path: []string{"m"}}
+	tests := []struct {
+		name string
+		m    map[any]any
+		want arrow.DataType
+	}{
+		{"same", map[any]any{1: int64(1), 2: int64(2)}, arrow.MapOf(arrow.PrimitiveTypes.Int64, arrow.PrimitiveTypes.Int64)},
+		{"int and float", map[any]any{1: int64(1), 2: 2.5, 3: int64(3)}, arrow.MapOf(arrow.PrimitiveTypes.Int64, arrow.PrimitiveTypes.Float64)},
+		{"mixed", map[any]any{1: int64(1), 2: true, 3: "x"}, arrow.MapOf(arrow.PrimitiveTypes.Int64, arrow.BinaryTypes.String)},
+		{"nested", map[any]any{1: int64(1), 2: []any{int64(1)}}, arrow.MapOf(arrow.PrimitiveTypes.Int64, arrow.BinaryTypes.String)},
+		{"null", map[any]any{1: nil, 2: 2.5}, arrow.MapOf(arrow.PrimitiveTypes.Int64, arrow.PrimitiveTypes.Float64)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// map iteration order is random, the result must not depend on it
+			for range 20 {
+				if got := mapType(f, tt.m); !arrow.TypeEqual(got, tt.want) {
+					t.Fatalf("mapType() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestUnifyReadBigInteger(t *testing.T) {
+	const big = "9999999999999999999999"
+	tests := []struct {
+		name string
+		opts []Option
+		want arrow.DataType
+	}{
+		{"decimal", nil, &arrow.Decimal128Type{Precision: 38, Scale: 0}},
+		{"string", []Option{WithBigIntegersAsString()}, arrow.BinaryTypes.String},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := NewBodkin(tt.opts...)
+			input := `{"id":1,"n":` + big + `}`
+			if err := u.Unify(input); err != nil {
+				t.Fatal(err)
+			}
+			sc, err := u.Schema()
+			if err != nil {
+				t.Fatal(err)
+			}
+			f, ok := sc.FieldsByName("n")
+			if !ok || !arrow.TypeEqual(f[0].Type, tt.want) {
+				t.Fatalf("n inferred as %v, want %v", f, tt.want)
+			}
+			r, err := u.NewReader()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer r.Release()
+			rec, err := r.ReadToRecord([]byte(input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer rec.Release()
+			col := rec.Column(sc.FieldIndices("n")[0])
+			var got string
+			switch col := col.(type) {
+			case *array.Decimal128:
+				got = col.Value(0).ToString(0)
+			case *array.String:
+				got = col.Value(0)
+			default:
+				t.Fatalf("n loaded as %T", col)
+			}
+			if got != big {
+				t.Errorf("n loaded as %s, want %s", got, big)
+			}
+		})
+	}
+}
+
+func TestNarrowestInt(t *testing.T) {
+	tests := []struct {
+		min, max int64
+		want     arrow.DataType
+	}{
+		{0, math.MaxUint8, arrow.PrimitiveTypes.Uint8},
+		{0, math.MaxUint8 + 1, arrow.PrimitiveTypes.Uint16},
+		{0, math.MaxUint16, arrow.PrimitiveTypes.Uint16},
+		{0, math.MaxUint16 + 1, arrow.PrimitiveTypes.Uint32},
+		{0, math.MaxUint32, arrow.PrimitiveTypes.Uint32},
+		{0, math.MaxUint32 + 1, arrow.PrimitiveTypes.Int64},
+		{math.MinInt8, math.MaxInt8, arrow.PrimitiveTypes.Int8},
+		{math.MinInt8 - 1, 0, arrow.PrimitiveTypes.Int16},
+		{-1, math.MaxInt8 + 1, arrow.PrimitiveTypes.Int16},
+		{math.MinInt16, math.MaxInt16, arrow.PrimitiveTypes.Int16},
+		{math.MinInt16 - 1, 0, arrow.PrimitiveTypes.Int32},
+		{-1, math.MaxInt16 + 1, arrow.PrimitiveTypes.Int32},
+		{math.MinInt32, math.MaxInt32, arrow.PrimitiveTypes.Int32},
+		{math.MinInt32 - 1, 0, arrow.PrimitiveTypes.Int64},
+		{-1, math.MaxInt32 + 1, arrow.PrimitiveTypes.Int64},
+	}
+	for _, tt := range tests {
+		if got := narrowestInt(tt.min, tt.max); !arrow.TypeEqual(got, tt.want) {
+			t.Errorf("narrowestInt(%d, %d) = %v, want %v", tt.min, tt.max, got, tt.want)
+		}
+	}
+}